@@ -0,0 +1,141 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// schemaExamples is the maximum number of string examples in an annotated
+// schema.
+const schemaExamples = 3
+
+type jscmAnnotations struct {
+	Description string   `json:"description,omitempty"`
+	Examples    []any    `json:"examples,omitempty"`
+	Count       int      `json:"x-jsum-count"`
+	Null        int      `json:"x-jsum-null,omitempty"`
+	Occurrence  *float64 `json:"x-jsum-occurrence,omitempty"`
+}
+
+// jscmAnnotated is a schema with annotations that are merged into the JSON
+// object of the schema.
+type jscmAnnotated struct {
+	Schema any
+	jscmAnnotations
+}
+
+func (a jscmAnnotated) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(a.Schema)
+	if err != nil {
+		return nil, err
+	}
+	var scm map[string]any
+	if json.Unmarshal(data, &scm) != nil {
+		scm = map[string]any{"allOf": []any{a.Schema}}
+	}
+	if data, err = json.Marshal(a.jscmAnnotations); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &scm); err != nil {
+		return nil, err
+	}
+	return json.Marshal(scm)
+}
+
+// annotate adds examples and counts of d to its schema scm if
+// SchemaOptions.Annotate is set.
+func annotate(d Deducer, scm any, opts *SchemaOptions) any {
+	if !opts.Annotate {
+		return scm
+	}
+	return jscmAnnotated{
+		Schema: scm,
+		jscmAnnotations: jscmAnnotations{
+			Examples: examplesOf(d),
+			Count:    d.super().Count,
+			Null:     d.super().Null,
+		},
+	}
+}
+
+// annotateMember adds the description and the occurrence ratio of member m
+// of o to the annotated schema scm.
+func annotateMember(o *Object, m *Member, scm any) any {
+	a, ok := scm.(jscmAnnotated)
+	if !ok || o.Objects() == 0 {
+		return scm
+	}
+	objs := float64(o.Objects())
+	occ := float64(m.Occurence) / objs
+	a.Occurrence = &occ
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "present in %.0f%% of objects", 100*occ)
+	if m.Nulls > 0 {
+		fmt.Fprintf(&sb, ", null in %.0f%%", 100*float64(m.Nulls)/objs)
+	}
+	if m.Empties > 0 {
+		fmt.Fprintf(&sb, ", empty in %.0f%%", 100*float64(m.Empties)/objs)
+	}
+	a.Description = sb.String()
+	return a
+}
+
+// examplesOf returns the most frequent strings, the range of numbers or the
+// observed booleans of d.
+func examplesOf(d Deducer) []any {
+	switch d := d.(type) {
+	case *String:
+		strs := slices.Collect(maps.Keys(d.Stats))
+		slices.SortFunc(strs, func(a, b string) int {
+			if c := d.Stats[b] - d.Stats[a]; c != 0 {
+				return c
+			}
+			return cmp.Compare(a, b)
+		})
+		var res []any
+		for _, s := range strs[:min(len(strs), schemaExamples)] {
+			res = append(res, s)
+		}
+		return res
+	case *Number:
+		switch {
+		case d.Count == d.Null:
+			return nil
+		case d.Min == d.Max:
+			return []any{d.Min}
+		}
+		return []any{d.Min, d.Max}
+	case *Boolean:
+		var res []any
+		if d.TrueNo > 0 {
+			res = append(res, true)
+		}
+		if d.FalseNo > 0 {
+			res = append(res, false)
+		}
+		return res
+	}
+	return nil
+}
@@ -18,24 +18,83 @@ along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 package jsum
 
-type Any struct{ dedBase }
+import (
+	"maps"
+	"slices"
+)
+
+type Any struct {
+	dedBase
+	// Types counts the non-null examples per JSON type.
+	Types map[JsonType]int `json:"types,omitempty"`
+}
 
 func newAny(cfg *Config, count, nulln int) *Any {
-	return &Any{dedBase{
-		cfg:   cfg,
-		Count: count,
-		Null:  nulln,
-	}}
+	return &Any{
+		dedBase: dedBase{
+			cfg:   cfg,
+			Count: count,
+			Null:  nulln,
+		},
+		Types: make(map[JsonType]int),
+	}
+}
+
+// newAnyFrom creates an Any that keeps the counts of d and the counts per
+// JSON type of d's examples.
+func newAnyFrom(d Deducer) *Any {
+	res := newAny(d.super().cfg, d.super().Count, d.super().Null)
+	if u, ok := d.(*Union); ok {
+		for _, v := range u.Variants {
+			res.addTypes(v)
+		}
+	} else {
+		res.addTypes(d)
+	}
+	return res
+}
+
+// addTypes adds the counts per JSON type of d's non-null examples.
+func (a *Any) addTypes(d Deducer) {
+	switch d := d.(type) {
+	case *Any:
+		for t, n := range d.Types {
+			a.Types[t] += n
+		}
+	case *Union:
+		for _, v := range d.Variants {
+			a.addTypes(v)
+		}
+	case *Unknown, Invalid:
+	default:
+		if n := d.super().Count - d.super().Null; n > 0 {
+			a.Types[d.JsonType()] += n
+		}
+	}
+}
+
+// TypeList returns the JSON types of a's examples ordered by descending count.
+func (a *Any) TypeList() []JsonType {
+	res := slices.Collect(maps.Keys(a.Types))
+	slices.SortFunc(res, func(s, t JsonType) int {
+		if c := a.Types[t] - a.Types[s]; c != 0 {
+			return c
+		}
+		return int(s) - int(t)
+	})
+	return res
 }
 
 func (*Any) JsonType() JsonType { return JsonAny }
 
 func (*Any) Accepts(v any, jt JsumType) float64 { return 1 }
 
-func (a *Any) Example(v any, _ JsumType, _ float64) Deducer {
+func (a *Any) Example(v any, jt JsumType, _ float64) Deducer {
 	a.Count++
 	if v == nil {
 		a.Null++
+	} else {
+		a.Types[jt.JsonType()]++
 	}
 	return a
 }
@@ -43,7 +102,7 @@ func (a *Any) Example(v any, _ JsumType, _ float64) Deducer {
 func (a *Any) Hash(dh DedupHash) uint64 {
 	hash := a.dedBase.startHash(JsonAny)
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], a)
+	dh.add(res, a)
 	return res
 }
 
@@ -52,9 +111,17 @@ func (a *Any) Equal(d Deducer) bool {
 	if !ok {
 		return false
 	}
-	return a.dedBase.Equal(&b.dedBase)
+	if !a.dedBase.Equal(&b.dedBase) {
+		return false
+	}
+	for jt := range JsonAny {
+		if (a.Types[jt] > 0) != (b.Types[jt] > 0) {
+			return false
+		}
+	}
+	return true
 }
 
-func (*Any) JSONSchema() any { return struct{}{} }
+func (*Any) JSONSchema(*SchemaOptions) any { return struct{}{} }
 
 func (a *Any) super() *dedBase { return &a.dedBase }
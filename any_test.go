@@ -0,0 +1,66 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestAny_keepTypes(t *testing.T) {
+	var d Deducer = NewUnknown(&testCfg)
+	for _, v := range []any{
+		map[string]any{"a": 1.0},
+		map[string]any{"a": 2.0},
+		"foo",
+		nil,
+		[]any{},
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	a, ok := d.(*Any)
+	if !ok {
+		t.Fatalf("deduced %T", d)
+	}
+	if a.Count != 5 || a.Null != 1 {
+		t.Errorf("unexpected counts %d / null %d", a.Count, a.Null)
+	}
+	want := map[JsonType]int{JsonObject: 2, JsonString: 1, JsonArray: 1}
+	if !maps.Equal(a.Types, want) {
+		t.Errorf("unexpected types %v", a.Types)
+	}
+	if l := AnyLabel(a); l != "Any (object 50%, array 25%, string 25%) [null:1/5 20%]" {
+		t.Errorf("unexpected label '%s'", l)
+	}
+}
+
+func TestObject_degradeToUnion(t *testing.T) {
+	cfg := Config{Union: UnionConfig{Combine: []TypeSet{AllTypes}}}
+	var d Deducer = NewUnknown(&cfg)
+	for _, v := range []any{map[string]any{"a": 1.0}, "foo"} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	u, ok := d.(*Union)
+	if !ok {
+		t.Fatalf("deduced %T", d)
+	}
+	if u.Count != 2 || len(u.Variants) != 2 {
+		t.Errorf("unexpected union count %d with %d variants", u.Count, len(u.Variants))
+	}
+}
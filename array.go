@@ -19,7 +19,10 @@ along with this program.  If not, see <https://www.gnu.org/licenses/>.
 package jsum
 
 import (
+	"cmp"
 	"encoding/binary"
+	"math/bits"
+	"strings"
 )
 
 type Array struct {
@@ -27,6 +30,25 @@ type Array struct {
 	MinLen int     `json:"min-len"`
 	MaxLen int     `json:"max-len"`
 	Elem   Deducer `json:"elements"`
+	// Tuple has one deducer per index as long as all arrays have the same
+	// length of at most ArrayConfig.MaxTuple and the type of each position is
+	// stable. Otherwise Tuple is nil.
+	Tuple []Deducer `json:"tuple,omitempty"`
+	// Elems is the total number of elements in all arrays.
+	Elems int `json:"elements-count"`
+	// Empty is the number of empty arrays.
+	Empty int `json:"empty"`
+	// LenHist is a histogram of array lengths. Bucket 0 counts empty arrays,
+	// bucket b > 0 counts arrays with length 2^(b-1) to 2^b-1.
+	LenHist []int `json:"len-hist,omitempty"`
+	// Scalars is the number of arrays with at least 2 elements that all are
+	// scalars of the same JSON type. UniqueNo, AscNo and DescNo count how many
+	// of these arrays had unique elements, were sorted in ascending or were
+	// sorted in descending order.
+	Scalars  int `json:"scalars,omitempty"`
+	UniqueNo int `json:"unique,omitempty"`
+	AscNo    int `json:"ascending,omitempty"`
+	DescNo   int `json:"descending,omitempty"`
 }
 
 func newArrJson(cfg *Config, count, nulln int) *Array {
@@ -54,38 +76,216 @@ func (a *Array) Example(v any, jt JsumType, _ float64) Deducer {
 		a.Null++
 		return a
 	}
-	switch jt.v {
-	case jsonArrAny:
+	if v, ok := arrElems(v, jt); ok {
 		a.Count++
-		v := v.([]any)
 		l := len(v)
+		a.tuple(v)
 		if a.MinLen < 0 {
 			a.MinLen, a.MaxLen = l, l
-		} else if l < a.MinLen {
-			a.MinLen = l
 		} else {
+			a.MinLen = min(a.MinLen, l)
 			a.MaxLen = max(a.MaxLen, l)
 		}
+		a.Elems += l
+		if l == 0 {
+			a.Empty++
+		}
+		a.addLen(l, 1)
+		a.order(v)
 		for _, e := range v {
 			a.Elem = a.Elem.Example(e, JsonTypeOf(e), UnknownAccept)
 		}
 		return a
-		// TODO case jsonArrRSlice:
 	}
-	return newAny(a.cfg, a.Count+1, a.Null) // TODO Why not union?
+	if jt.t == JsonArray {
+		// a union would pass the example back to a
+		return newAnyFrom(a).Example(v, jt, UnknownAccept)
+	}
+	return newUnion(a).Example(v, jt, UnknownAccept)
+}
+
+// tuple updates the per index deducers with the array example v. It must be
+// called before MinLen is updated.
+func (a *Array) tuple(v []any) {
+	if a.MinLen < 0 {
+		if l := len(v); l > 0 && a.cfg != nil && l <= a.cfg.Array.MaxTuple {
+			a.Tuple = make([]Deducer, l)
+			for i := range a.Tuple {
+				a.Tuple[i] = NewUnknown(a.cfg)
+			}
+		} else {
+			return
+		}
+	}
+	if len(v) != len(a.Tuple) {
+		a.Tuple = nil
+		return
+	}
+	for i, e := range v {
+		if a.Tuple[i] = a.Tuple[i].Example(e, JsonTypeOf(e), UnknownAccept); !tupleStable(a.Tuple[i]) {
+			a.Tuple = nil
+			return
+		}
+	}
+}
+
+func tupleStable(d Deducer) bool {
+	switch d.JsonType() {
+	case JsonUnion, JsonAny, jsonInvalid:
+		return false
+	}
+	return true
+}
+
+func lenBucket(l int) int { return bits.Len(uint(l)) }
+
+// LenBucketRange returns the range of array lengths counted in bucket b of
+// Array.LenHist.
+func LenBucketRange(b int) (minLen, maxLen int) {
+	if b == 0 {
+		return 0, 0
+	}
+	return 1 << (b - 1), 1<<b - 1
+}
+
+func (a *Array) addLen(l, n int) { a.addLenBucket(lenBucket(l), n) }
+
+func (a *Array) addLenBucket(b, n int) {
+	if len(a.LenHist) <= b {
+		a.LenHist = append(a.LenHist, make([]int, b+1-len(a.LenHist))...)
+	}
+	a.LenHist[b] += n
+}
+
+// MeanLen returns the mean length of all non-null arrays.
+func (a *Array) MeanLen() float64 {
+	if n := a.Count - a.Null; n > 0 {
+		return float64(a.Elems) / float64(n)
+	}
+	return 0
+}
+
+// order checks uniqueness and sort order of arrays of scalars.
+func (a *Array) order(v []any) {
+	if len(v) < 2 {
+		return
+	}
+	jt := JsonTypeOf(v[0]).JsonType()
+	if !jt.scalar() {
+		return
+	}
+	for _, e := range v[1:] {
+		if JsonTypeOf(e).JsonType() != jt {
+			return
+		}
+	}
+	a.Scalars++
+	asc, desc := true, true
+	seen := make(map[any]bool, len(v))
+	unique := true
+	for i, e := range v {
+		k := scalarKey(e)
+		if seen[k] {
+			unique = false
+		}
+		seen[k] = true
+		if i > 0 {
+			switch c := compareScalar(v[i-1], e); {
+			case c < 0:
+				desc = false
+			case c > 0:
+				asc = false
+			}
+		}
+	}
+	if unique {
+		a.UniqueNo++
+	}
+	if asc {
+		a.AscNo++
+	}
+	if desc {
+		a.DescNo++
+	}
+}
+
+// scalarKey normalises numbers of different Go types for comparison.
+func scalarKey(v any) any {
+	if jt := JsonTypeOf(v); jt.t == JsonNumber {
+		x, _ := asNumber(v, jt.v)
+		return x
+	}
+	return v
+}
+
+// compareScalar compares scalar values of the same JSON type.
+func compareScalar(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case bool:
+		switch b := b.(bool); {
+		case a == b:
+			return 0
+		case b:
+			return -1
+		}
+		return 1
+	}
+	x, _ := asNumber(a, JsonTypeOf(a).v)
+	y, _ := asNumber(b, JsonTypeOf(b).v)
+	return cmp.Compare(x, y)
+}
+
+// multiNo returns the number of arrays with at least 2 elements.
+func (a *Array) multiNo() (n int) {
+	for b := 2; b < len(a.LenHist); b++ {
+		n += a.LenHist[b]
+	}
+	return n
+}
+
+// allChecked reports whether all arrays with at least 2 elements were arrays
+// of scalars for which the order was checked.
+func (a *Array) allChecked() bool {
+	return a.Scalars > 0 && a.Scalars == a.multiNo()
+}
+
+// AlwaysUnique reports whether all arrays with at least 2 elements were
+// arrays of scalars with unique elements.
+func (a *Array) AlwaysUnique() bool { return a.allChecked() && a.UniqueNo == a.Scalars }
+
+// AlwaysAscending reports whether all arrays with at least 2 elements were
+// arrays of scalars sorted in ascending order.
+func (a *Array) AlwaysAscending() bool { return a.allChecked() && a.AscNo == a.Scalars }
+
+// AlwaysDescending reports whether all arrays with at least 2 elements were
+// arrays of scalars sorted in descending order.
+func (a *Array) AlwaysDescending() bool { return a.allChecked() && a.DescNo == a.Scalars }
+
+// IsTuple reports whether a is considered as a tuple, i.e. there is a
+// deducer for each index and there were at least two non-null arrays.
+func (a *Array) IsTuple() bool {
+	return len(a.Tuple) > 0 && a.Count-a.Null > 1
 }
 
 func (a *Array) Hash(dh DedupHash) uint64 {
 	hash := a.dedBase.startHash(JsonArray)
-	if a.MaxLen == 0 {
+	if a.MinLen == 0 {
 		hash.WriteByte(0)
 	} else {
 		hash.WriteByte(1)
 	}
 	eh := a.Elem.Hash(dh)
 	binary.Write(hash, hashEndian, eh)
+	if a.IsTuple() {
+		binary.Write(hash, hashEndian, uint32(len(a.Tuple)))
+		for _, t := range a.Tuple {
+			binary.Write(hash, hashEndian, t.Hash(dh))
+		}
+	}
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], a)
+	dh.add(res, a)
 	return res
 }
 
@@ -100,18 +300,40 @@ func (a *Array) Equal(d Deducer) bool {
 	if (a.MinLen == 0) != (b.MinLen == 0) {
 		return false
 	}
+	if a.IsTuple() != b.IsTuple() {
+		return false
+	}
+	if a.IsTuple() {
+		if len(a.Tuple) != len(b.Tuple) {
+			return false
+		}
+		for i, t := range a.Tuple {
+			if !t.Equal(b.Tuple[i]) {
+				return false
+			}
+		}
+	}
 	return a.Elem.Equal(b.Elem)
 }
 
-func (a *Array) JSONSchema() any {
-	res := jscmArray{
-		jscmType: jscmType{Type: "array"},
-		Items:    a.Elem.JSONSchema(),
-		MinItems: a.MinLen,
-		MaxItems: a.MaxLen,
+func (a *Array) JSONSchema(opts *SchemaOptions) any {
+	res := jscmArray{jscmType: schemaType("array", a.Null > 0)}
+	loose := opts.Strictness == SchemaLoose
+	if a.IsTuple() {
+		res.PrefixItems = make([]any, len(a.Tuple))
+		for i, t := range a.Tuple {
+			res.PrefixItems[i] = schemaOf(t, opts)
+		}
+		if !loose {
+			res.Items = false
+		}
+	} else {
+		res.Items = schemaOf(a.Elem, opts)
 	}
-	if a.Null > 0 {
-		return []any{"null", res}
+	if !loose {
+		mi, ma := a.MinLen, a.MaxLen
+		res.MinItems, res.MaxItems = &mi, &ma
+		res.UniqueItems = a.AlwaysUnique()
 	}
 	return res
 }
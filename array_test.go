@@ -0,0 +1,144 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestArray_tuple(t *testing.T) {
+	cfg := Config{
+		Union: UnionConfig{Combine: []TypeSet{AllTypes}},
+		Array: ArrayConfig{MaxTuple: 4},
+	}
+	deduce := func(vs ...[]any) *Array {
+		var d Deducer = NewUnknown(&cfg)
+		for _, v := range vs {
+			d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+		}
+		a, ok := d.(*Array)
+		if !ok {
+			t.Fatalf("deduced not array but %T", d)
+		}
+		return a
+	}
+	a := deduce(
+		[]any{"GET", "/path", 200.0},
+		[]any{"POST", "/other", 404.0},
+	)
+	if !a.IsTuple() {
+		t.Fatal("tuple not detected")
+	}
+	if _, ok := a.Tuple[2].(*Number); !ok {
+		t.Errorf("tuple[2] not number but %T", a.Tuple[2])
+	}
+	if _, ok := a.Elem.(*Union); !ok {
+		t.Errorf("elements not union but %T", a.Elem)
+	}
+	if a = deduce([]any{1.0, 2.0}, []any{1.0, 2.0, 3.0}); a.IsTuple() {
+		t.Error("tuple with varying length")
+	}
+	if a = deduce([]any{1.0, "a"}, []any{"b", 2.0}); a.IsTuple() {
+		t.Error("tuple with unstable types")
+	}
+	if a = deduce([]any{1.0, 2.0, 3.0, 4.0, 5.0}, []any{1.0, 2.0, 3.0, 4.0, 5.0}); a.IsTuple() {
+		t.Error("tuple exceeds max length")
+	}
+}
+
+func TestArray_lenStats(t *testing.T) {
+	var d Deducer = NewUnknown(&testCfg)
+	for _, v := range [][]any{
+		{1.0, 2.0, 3.0},
+		{},
+		{1.0},
+		{1.0, 2.0, 3.0, 4.0, 5.0},
+		{},
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	a := d.(*Array)
+	if a.MinLen != 0 || a.MaxLen != 5 {
+		t.Errorf("unexpected length range %d..%d", a.MinLen, a.MaxLen)
+	}
+	if a.Elems != 9 || a.Empty != 2 {
+		t.Errorf("unexpected elements %d / empty %d", a.Elems, a.Empty)
+	}
+	if m := a.MeanLen(); m != 1.8 {
+		t.Errorf("unexpected mean length %f", m)
+	}
+	if !slices.Equal(a.LenHist, []int{2, 1, 1, 1}) {
+		t.Errorf("unexpected length histogram %v", a.LenHist)
+	}
+}
+
+func TestArray_order(t *testing.T) {
+	deduce := func(vs ...[]any) *Array {
+		var d Deducer = NewUnknown(&testCfg)
+		for _, v := range vs {
+			d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+		}
+		return d.(*Array)
+	}
+	a := deduce([]any{1.0, 2.0, 3.0}, []any{"a"}, []any{-1.0, 4})
+	if !a.AlwaysUnique() || !a.AlwaysAscending() || a.AlwaysDescending() {
+		t.Errorf("unexpected order unique:%d asc:%d desc:%d of %d",
+			a.UniqueNo, a.AscNo, a.DescNo, a.Scalars)
+	}
+	a = deduce([]any{"c", "b", "b"}, []any{"z", "a"})
+	if a.AlwaysUnique() || a.AlwaysAscending() || !a.AlwaysDescending() {
+		t.Errorf("unexpected order unique:%d asc:%d desc:%d of %d",
+			a.UniqueNo, a.AscNo, a.DescNo, a.Scalars)
+	}
+	a = deduce([]any{true, 1.0}, []any{map[string]any{}, map[string]any{}})
+	if a.Scalars != 0 {
+		t.Errorf("mixed arrays checked: %d", a.Scalars)
+	}
+	a = deduce([]any{1.0, 2.0}, []any{map[string]any{"a": 1.0}, map[string]any{"a": 1.0}})
+	if a.AlwaysUnique() || a.AlwaysAscending() || a.AlwaysDescending() {
+		t.Errorf("unchecked arrays reported unique:%d asc:%d desc:%d of %d",
+			a.UniqueNo, a.AscNo, a.DescNo, a.Scalars)
+	}
+	b := deduce([]any{"x"}, []any{3.0, 4.0})
+	a = merge(a, b).(*Array)
+	if a.AlwaysUnique() {
+		t.Errorf("merged unchecked arrays reported unique")
+	}
+}
+
+func TestArray_reflectSlice(t *testing.T) {
+	d := deduceAll(&testCfg, []any{"a"}, []string{"b", "c"})
+	a, ok := d.(*Array)
+	if !ok {
+		t.Fatalf("deduced %T", d)
+	}
+	if s, ok := a.Elem.(*String); !ok || a.Count != 2 || s.Count != 3 {
+		t.Errorf("unexpected array %+v of %T", a, a.Elem)
+	}
+	cfg := Config{Union: UnionConfig{Combine: []TypeSet{AllTypes}}}
+	d = deduceAll(&cfg, "s", []int{1}, []string{"b"})
+	u, ok := d.(*Union)
+	if !ok || len(u.Variants) != 2 {
+		t.Fatalf("deduced %T %+v", d, d)
+	}
+	if a, ok := u.Variants[1].(*Array); !ok || a.Count != 2 {
+		t.Errorf("unexpected array variant %+v", u.Variants[1])
+	}
+}
@@ -73,7 +73,7 @@ func (a *Boolean) Hash(dh DedupHash) uint64 {
 		}
 	}
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], a)
+	dh.add(res, a)
 	return res
 }
 
@@ -82,22 +82,18 @@ func (s *Boolean) Equal(d Deducer) bool {
 	if !ok {
 		return false
 	}
-	if (s.FalseNo > 0) != (b.FalseNo > 0) {
+	if s.cfg.Dedup.Bool&DedupBoolFalse != 0 && (s.FalseNo > 0) != (b.FalseNo > 0) {
 		return false
 	}
-	if (s.TrueNo > 0) != (b.TrueNo > 0) {
+	if s.cfg.Dedup.Bool&DedupBoolTrue != 0 && (s.TrueNo > 0) != (b.TrueNo > 0) {
 		return false
 	}
 	res := s.dedBase.Equal(&b.dedBase)
 	return res
 }
 
-func (a *Boolean) JSONSchema() any {
-	res := jscmString{jscmType: jscmType{Type: "boolean"}}
-	if a.Null > 0 {
-		return []any{"null", res}
-	}
-	return res
+func (a *Boolean) JSONSchema(*SchemaOptions) any {
+	return schemaType("boolean", a.Null > 0)
 }
 
 func (s *Boolean) super() *dedBase { return &s.dedBase }
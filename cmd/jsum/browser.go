@@ -397,8 +397,14 @@ func nodePath(p []*tview.TreeNode) string {
 		switch ref := info.(type) {
 		case string:
 			fmt.Fprintf(&sb, ".%s", ref)
+		case int:
+			fmt.Fprintf(&sb, "[%d]", ref)
 		case *jsum.Array:
-			sb.WriteString("[*]")
+			if !ref.IsTuple() {
+				sb.WriteString("[*]")
+			}
+		case *jsum.Map:
+			sb.WriteString(".*")
 		}
 	}
 	return sb.String()
@@ -34,6 +34,9 @@ type lbFmtFunc func(string) string
 type searchBuild = map[string][]*tview.TreeNode
 
 func browseTree(scm jsum.Deducer, lff lbFmtFunc, srb searchBuild) (res *tview.TreeNode) {
+	if n := jsum.TypeName(scm); n != "" {
+		lff = typeNameFmt(lff, n)
+	}
 	switch scm := scm.(type) {
 	case *jsum.String:
 		res = browseString(scm, lff, srb)
@@ -45,8 +48,16 @@ func browseTree(scm jsum.Deducer, lff lbFmtFunc, srb searchBuild) (res *tview.Tr
 		res = browseBool(scm, lff)
 	case *jsum.Array:
 		res = browseArray(scm, lff, srb)
+	case *jsum.Map:
+		res = browseMap(scm, lff, srb)
+	case *jsum.Tagged:
+		res = browseTagged(scm, lff, srb)
+	case *jsum.Grouped:
+		res = browseGrouped(scm, lff, srb)
 	case *jsum.Union:
 		res = browseUnion(scm, lff, srb)
+	case *jsum.Recursion:
+		res = browseRecursion(scm, lff)
 	case *jsum.Any:
 		res = browseAny(scm, lff)
 	case *jsum.Unknown:
@@ -60,6 +71,13 @@ func browseTree(scm jsum.Deducer, lff lbFmtFunc, srb searchBuild) (res *tview.Tr
 	return res
 }
 
+// typeNameFmt prefixes labels with the name of a reused type.
+func typeNameFmt(lff lbFmtFunc, name string) lbFmtFunc {
+	return func(s string) string {
+		return "[green::]" + name + "[-::]: " + lff(s)
+	}
+}
+
 func browseString(scm *jsum.String, lff lbFmtFunc, srb searchBuild) (res *tview.TreeNode) {
 	fldNode := stdFolder(lff(jsum.StringLabel(scm)))
 	res = tview.NewTreeNode(fldNode.label(false))
@@ -97,7 +115,12 @@ func browseNumber(scm *jsum.Number, lff lbFmtFunc) (res *tview.TreeNode) {
 }
 
 func browseObject(scm *jsum.Object, lff lbFmtFunc, srb searchBuild) (res *tview.TreeNode) {
-	fldNode := stdFolder(lff(jsum.ObjectLabel(scm)))
+	naming := scm.Naming()
+	label := lff(jsum.ObjectLabel(scm))
+	if !naming.OK() {
+		label += " [red::]⚠ naming[-::]"
+	}
+	fldNode := stdFolder(label)
 	res = tview.NewTreeNode(fldNode.label(true))
 	initRef(res, &fldNode, scm)
 	nms := slices.Collect(maps.Keys(scm.Members))
@@ -105,16 +128,29 @@ func browseObject(scm *jsum.Object, lff lbFmtFunc, srb searchBuild) (res *tview.
 	var sb strings.Builder
 	for _, a := range nms {
 		m := scm.Members[a]
-		if m.Occurence < scm.Count {
+		if scm.Absent(&m) > 0 {
 			fmt.Fprintf(&sb, "[::b]\"%s\"[::-] [blue::]optional[-::] (%d/%d %.0f%%)",
 				a,
 				m.Occurence,
-				scm.Count,
-				100*float64(m.Occurence)/float64(scm.Count),
+				scm.Objects(),
+				100*float64(m.Occurence)/float64(scm.Objects()),
 			)
 		} else {
 			fmt.Fprintf(&sb, "[::bu]\"%s\"[::-] [orange::]mandatory[-::] (%d×)", a, m.Occurence)
 		}
+		if l := jsum.MemberValuesLabel(scm, &m); l != "" {
+			sb.WriteByte(' ')
+			sb.WriteString(tview.Escape(l))
+		}
+		if m.Duplicates > 0 {
+			fmt.Fprintf(&sb, " [red::]duplicate[-::] (%d×)", m.Duplicates)
+		}
+		for _, sim := range naming.Similar {
+			if slices.Contains(sim, a) {
+				sb.WriteString(" [red::]≈ similar name[-::]")
+				break
+			}
+		}
 		sb.WriteByte(':')
 		fldMember := folder{
 			text:  sb.String(),
@@ -129,6 +165,60 @@ func browseObject(scm *jsum.Object, lff lbFmtFunc, srb searchBuild) (res *tview.
 		res.AddChild(nm)
 		srb[a] = append(srb[a], nm)
 	}
+	if len(scm.Shapes) > 1 {
+		res.AddChild(browseShapes(scm))
+	}
+	if len(scm.Orders) > 0 {
+		res.AddChild(browseOrders(scm))
+	}
+	if !naming.OK() {
+		fldNaming := stdFolder("[::i]Naming issues[::-]")
+		nn := tview.NewTreeNode(fldNaming.label(false))
+		initRef(nn, &fldNaming, nil)
+		for _, l := range jsum.NamingLabels(&naming) {
+			nn.AddChild(tview.NewTreeNode(" [red::]" + l + "[-::]"))
+		}
+		nn.SetExpanded(false)
+		fldNaming.fold(nn)
+		res.AddChild(nn)
+	}
+	fldNode.fold(res)
+	return res
+}
+
+func browseShapes(scm *jsum.Object) (res *tview.TreeNode) {
+	fldNode := stdFolder("[::i]" + jsum.ShapesLabel(scm) + "[::-]")
+	res = tview.NewTreeNode(fldNode.label(false))
+	initRef(res, &fldNode, nil)
+	for _, sh := range scm.ShapeList() {
+		res.AddChild(tview.NewTreeNode(" " + jsum.ShapeLabel(sh)))
+	}
+	co := scm.CoOccurence()
+	color := "[blue::]"
+	if !co.Complete {
+		color = "[yellow::]"
+	}
+	for _, l := range jsum.CoOccurenceLabels(co) {
+		res.AddChild(tview.NewTreeNode(" " + color + l + "[-::]"))
+	}
+	res.SetExpanded(false)
+	fldNode.fold(res)
+	return res
+}
+
+func browseOrders(scm *jsum.Object) (res *tview.TreeNode) {
+	fldNode := stdFolder("[::i]" + jsum.OrdersLabel(scm) + "[::-]")
+	res = tview.NewTreeNode(fldNode.label(false))
+	initRef(res, &fldNode, nil)
+	if order, ok := scm.MemberOrder(); ok {
+		res.AddChild(tview.NewTreeNode(
+			" [blue::]canonical: [" + strings.Join(order, ", ") + "][-::]",
+		))
+	}
+	for _, sh := range scm.OrderList() {
+		res.AddChild(tview.NewTreeNode(" " + jsum.OrderLabel(sh)))
+	}
+	res.SetExpanded(false)
 	fldNode.fold(res)
 	return res
 }
@@ -142,7 +232,32 @@ func browseBool(scm *jsum.Boolean, lff lbFmtFunc) (res *tview.TreeNode) {
 func browseArray(scm *jsum.Array, lff lbFmtFunc, srb searchBuild) (res *tview.TreeNode) {
 	res = tview.NewTreeNode("┬ " + lff(jsum.ArrayLabel(scm)) + ":")
 	initRef(res, nil, scm)
-	res.AddChild(browseTree(scm.Elem, noFmt, srb))
+	if !scm.IsTuple() {
+		if h := jsum.LenHistLabel(scm); h != "" {
+			res.AddChild(tview.NewTreeNode(" [::i]" + h + "[::-]"))
+		}
+		res.AddChild(browseTree(scm.Elem, noFmt, srb))
+		return res
+	}
+	for i, t := range scm.Tuple {
+		fldIdx := folder{
+			text:  fmt.Sprintf("[::b][%d][::-]:", i),
+			open:  "┯ ",
+			close: "━ ",
+		}
+		in := tview.NewTreeNode(fldIdx.label(true))
+		initRef(in, &fldIdx, i)
+		in.AddChild(browseTree(t, noFmt, srb))
+		fldIdx.fold(in)
+		res.AddChild(in)
+	}
+	return res
+}
+
+func browseMap(scm *jsum.Map, lff lbFmtFunc, srb searchBuild) (res *tview.TreeNode) {
+	res = tview.NewTreeNode("┬ " + lff(jsum.MapLabel(scm)) + ":")
+	initRef(res, nil, scm)
+	res.AddChild(browseTree(scm.Value, noFmt, srb))
 	return res
 }
 
@@ -157,12 +272,63 @@ func browseUnion(scm *jsum.Union, lff lbFmtFunc, srb searchBuild) (res *tview.Tr
 	return res
 }
 
+func browseTagged(scm *jsum.Tagged, lff lbFmtFunc, srb searchBuild) (res *tview.TreeNode) {
+	fldNode := stdFolder(lff(jsum.TaggedLabel(scm)) + ":")
+	res = tview.NewTreeNode(fldNode.label(true))
+	initRef(res, &fldNode, scm)
+	for i := range scm.Variants {
+		v := &scm.Variants[i]
+		fldVar := folder{
+			text:  "[::b]" + jsum.TagVariantLabel(scm, v) + "[::-]:",
+			open:  "┯ ",
+			close: "━ ",
+		}
+		vn := tview.NewTreeNode(fldVar.label(true))
+		initRef(vn, &fldVar, nil)
+		vn.AddChild(browseTree(v.Ded, noFmt, srb))
+		fldVar.fold(vn)
+		res.AddChild(vn)
+		for _, val := range v.Values {
+			srb[val] = append(srb[val], vn)
+		}
+	}
+	fldNode.fold(res)
+	return res
+}
+
+func browseGrouped(scm *jsum.Grouped, lff lbFmtFunc, srb searchBuild) (res *tview.TreeNode) {
+	fldNode := stdFolder(lff(jsum.GroupedLabel(scm)) + ":")
+	res = tview.NewTreeNode(fldNode.label(true))
+	initRef(res, &fldNode, scm)
+	for _, k := range scm.GroupKeys() {
+		fldGrp := folder{
+			text:  "[::b]" + jsum.GroupLabel(scm, k) + "[::-]:",
+			open:  "┯ ",
+			close: "━ ",
+		}
+		gn := tview.NewTreeNode(fldGrp.label(true))
+		initRef(gn, &fldGrp, nil)
+		gn.AddChild(browseTree(scm.Groups[k], noFmt, srb))
+		fldGrp.fold(gn)
+		res.AddChild(gn)
+		srb[k] = append(srb[k], gn)
+	}
+	fldNode.fold(res)
+	return res
+}
+
 func browseAny(scm *jsum.Any, lff lbFmtFunc) (res *tview.TreeNode) {
 	res = tview.NewTreeNode(" " + lff(jsum.AnyLabel(scm)))
 	initRef(res, nil, scm)
 	return res
 }
 
+func browseRecursion(scm *jsum.Recursion, lff lbFmtFunc) (res *tview.TreeNode) {
+	res = tview.NewTreeNode(" " + lff(jsum.RecursionLabel(scm)))
+	initRef(res, nil, scm)
+	return res
+}
+
 func browseUnknown(scm *jsum.Unknown, lff lbFmtFunc) (res *tview.TreeNode) {
 	res = tview.NewTreeNode(" " + lff(jsum.UnknownLabel(scm)))
 	initRef(res, nil, scm)
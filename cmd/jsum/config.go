@@ -0,0 +1,122 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"git.fractalqb.de/fractalqb/jsum"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the content of a -config file. Members that are missing in
+// the file keep their current values.
+type fileConfig struct {
+	Jsum    *jsum.Config        `json:"jsum"`
+	Schema  *jsum.SchemaOptions `json:"schema"`
+	Summary summaryConfig       `json:"summary"`
+}
+
+type summaryConfig struct {
+	Tree    string `json:"tree"`
+	Strings int    `json:"strings"`
+	Shapes  int    `json:"shapes"`
+}
+
+func currentConfig() fileConfig {
+	return fileConfig{
+		Jsum:   &cfg,
+		Schema: &fSchemaOpt,
+		Summary: summaryConfig{
+			Tree:    fTreeStyle,
+			Strings: fStrMax,
+			Shapes:  fShapeMax,
+		},
+	}
+}
+
+// loadConfig reads a JSON or YAML config file into the current configuration.
+func loadConfig(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(name) {
+	case ".yml", ".yaml":
+		var tmp any
+		if err := yaml.Unmarshal(data, &tmp); err != nil {
+			return fmt.Errorf("config %s: %w", name, err)
+		}
+		if data, err = json.Marshal(tmp); err != nil {
+			return fmt.Errorf("config %s: %w", name, err)
+		}
+	}
+	fc := currentConfig()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return fmt.Errorf("config %s: %w", name, err)
+	}
+	fTreeStyle = fc.Summary.Tree
+	fStrMax = fc.Summary.Strings
+	fShapeMax = fc.Summary.Shapes
+	return nil
+}
+
+func writeConfig(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "   ")
+	return enc.Encode(currentConfig())
+}
+
+// typeSetsFlag sets a list of type sets from a comma separated list, e.g.
+// "string|number|boolean,object,array".
+type typeSetsFlag struct{ sets *[]jsum.TypeSet }
+
+func (f typeSetsFlag) String() string {
+	if f.sets == nil {
+		return ""
+	}
+	strs := make([]string, len(*f.sets))
+	for i, ts := range *f.sets {
+		strs[i] = ts.String()
+	}
+	return strings.Join(strs, ",")
+}
+
+func (f typeSetsFlag) Set(s string) error {
+	var sets []jsum.TypeSet
+	if s != "" {
+		for str := range strings.SplitSeq(s, ",") {
+			var ts jsum.TypeSet
+			if err := ts.UnmarshalText([]byte(str)); err != nil {
+				return err
+			}
+			sets = append(sets, ts)
+		}
+	}
+	*f.sets = sets
+	return nil
+}
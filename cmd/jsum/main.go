@@ -31,6 +31,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"git.fractalqb.de/fractalqb/jsum"
 	"git.fractalqb.de/fractalqb/tetrta"
@@ -42,25 +43,34 @@ var (
 		Union: jsum.UnionConfig{
 			MergeRejectMax: math.SmallestNonzeroFloat64,
 			Combine:        []jsum.TypeSet{jsum.AllTypes},
-			// Combine: []jsum.TypeSet{
-			// 	jsum.NewTypeSet(jsum.JsonString, jsum.JsonNumber, jsum.JsonBoolean),
-			// 	jsum.NewTypeSet(jsum.JsonObject),
-			// 	jsum.NewTypeSet(jsum.JsonArray),
-			// },
 		},
 		Dedup: jsum.DedupConfig{
 			Bool:   jsum.DedupBoolFalse | jsum.DedupBoolTrue,
 			Number: jsum.DedpuNumberIntFloat | jsum.DedupNumberNeg,
 			String: jsum.DedupStringEmpty,
 		},
+		Map: jsum.MapConfig{
+			MinMembers:   32,
+			MaxOccurence: 0.1,
+		},
+		Object: jsum.ObjectConfig{MaxShapes: 64, MaxOrders: 16},
+		Array:  jsum.ArrayConfig{MaxTuple: 8},
 	}
 	fTreeStyle = "draw"
 	fStrMax    = 6
+	fShapeMax  = 3
 	fTypes     bool
+	fNaming    bool
+	fSimilar   float64
 	fArgs      string
 	fOut       string
 	fState     string
 	fSchema    string
+	fSchemaOpt jsum.SchemaOptions
+	fOpenAPI   string
+	fGroupBy   string
+	fConfig    string
+	fShowCfg   bool
 )
 
 const (
@@ -90,6 +100,7 @@ func usage() {
 	fmt.Fprintln(w, `Generate a summary from example JSON or YAML files.
 
   Usage: jsum [flags] <JSON/YAML file>|'-'...
+         jsum validate -state <file> [-config <file>] <JSON/YAML file>|'-'...
 
 Without printing and schema generation, JSUM will launch an interactive browser
 for the summary in the terminal.
@@ -99,19 +110,45 @@ FLAGS:`)
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		validateMain(os.Args[2:])
+		return
+	}
 	flag.Usage = usage
 	flag.StringVar(&fTreeStyle, "tree", fTreeStyle,
 		"Select style for tree printing from: ascii, draw, items (env: "+envJsumTree+")\n")
 	flag.IntVar(&fStrMax, "strings", fStrMax,
 		"Max number of strings values to print per property (env: "+envJsumStrings+")\n")
+	flag.IntVar(&fShapeMax, "shapes", fShapeMax,
+		"Max number of object shapes to print per object (0: no shape analysis)")
 	flag.BoolVar(&fTypes, "types", fTypes,
 		"Find reused types (experimental)")
+	flag.BoolVar(&fNaming, "naming", fNaming,
+		"Report objects with mixed member naming styles or similar names")
+	flag.Float64Var(&fSimilar, "similar", fSimilar,
+		`Report clusters of objects that are at least this similar (0..1) but
+not equal. 0 disables the report.`)
 	flag.StringVar(&fArgs, "a", fArgs,
 		"Read args from file ('-' reads from stdin)")
 	flag.StringVar(&fOut, "o", fOut,
 		"Print summary to file ('-' writes to stdout)")
 	flag.StringVar(&fSchema, "schema", fSchema,
 		"Generate JSON Schema file")
+	flag.StringVar(&fOpenAPI, "openapi", fOpenAPI,
+		`Generate OpenAPI 3.1 document with the schema in components/schemas.
+Written as YAML if the file name ends with .yaml or .yml.`)
+	flag.TextVar(&fSchemaOpt.Draft, "schema-draft", fSchemaOpt.Draft,
+		"JSON Schema version to generate: 2020-12, 2019-09 or 07")
+	flag.TextVar(&fSchemaOpt.Strictness, "schema-strictness", fSchemaOpt.Strictness,
+		`How closely the JSON Schema follows the examples: loose (only types and
+required members), observed (observed bounds) or strict (also closed
+objects, enums, patterns and formats)`)
+	flag.BoolVar(&fSchemaOpt.Annotate, "schema-annotate", fSchemaOpt.Annotate,
+		`Add examples, descriptions and x-jsum-* counts to the JSON Schema`)
+	flag.StringVar(&fSchemaOpt.ID, "schema-id", fSchemaOpt.ID,
+		`Set "$id" of the generated JSON Schema`)
+	flag.StringVar(&fSchemaOpt.Title, "schema-title", fSchemaOpt.Title,
+		`Set "title" of the generated JSON Schema`)
 	flag.StringVar(&fState, "state", fState,
 		`Keep deduced schema in state file.
 This can be used for incremental refinement or simply to browse without
@@ -119,13 +156,70 @@ analysing the examples again.`)
 	flag.Float64Var(&cfg.Union.MergeRejectMax, "union-merge", cfg.Union.MergeRejectMax,
 		`Maximum acceptance value that is rejected for merging into an existing
 union variant. (env: `+envJsumUnionMerge+")\n")
+	flag.TextVar(&cfg.Union.Accept, "union-accept", cfg.Union.Accept,
+		`Strategy to rate how well an object fits an object variant of a union:
+jaccard, weighted (by member frequency) or strict (identical members)`)
+	flag.BoolVar(&cfg.Union.AcceptAnyType, "union-any-type", cfg.Union.AcceptAnyType,
+		"Count members as common for union acceptance even if their types differ")
+	flag.Func("tags",
+		`Comma separated names of string members that discriminate object
+variants (tagged unions)`,
+		func(s string) error {
+			cfg.Union.Tags = strings.Split(s, ",")
+			return nil
+		})
+	flag.Var(typeSetsFlag{&cfg.Union.Combine}, "union-combine",
+		`Comma separated combinations of JSON types that may coexist in a union,
+e.g. 'string|number|boolean,object,array'. 'all' allows any combination.`)
+	flag.TextVar(&cfg.Dedup.Bool, "dedup-bool", cfg.Dedup.Bool,
+		"Criteria to distinguish booleans when finding reused types: true|false")
+	flag.TextVar(&cfg.Dedup.Number, "dedup-number", cfg.Dedup.Number,
+		`Criteria to distinguish numbers when finding reused types:
+int-float|frac|min|max|neg|pos`)
+	flag.TextVar(&cfg.Dedup.String, "dedup-string", cfg.Dedup.String,
+		"Criteria to distinguish strings when finding reused types: empty")
+	flag.IntVar(&cfg.Object.MaxShapes, "object-shapes", cfg.Object.MaxShapes,
+		"Maximum number of distinct member sets tracked per object")
+	flag.IntVar(&cfg.Object.MaxOrders, "object-orders", cfg.Object.MaxOrders,
+		"Maximum number of distinct member orders tracked per object")
+	flag.IntVar(&cfg.Array.MaxTuple, "array-tuple", cfg.Array.MaxTuple,
+		"Maximum length of arrays checked to be tuples (0: no tuple detection)")
+	flag.StringVar(&fConfig, "config", fConfig,
+		`Read configuration from JSON or YAML file. Flags given on the command
+line take precedence.`)
+	flag.BoolVar(&fShowCfg, "show-config", fShowCfg,
+		"Print the effective configuration as JSON and exit")
+	flag.StringVar(&fGroupBy, "group-by", fGroupBy,
+		`Keep a separate summary for each distinct value at the given path,
+e.g. '$.type'`)
+	flag.IntVar(&cfg.Map.MinMembers, "map-members", cfg.Map.MinMembers,
+		`Minimum number of distinct members for an object to be considered a map.
+Zero disables map detection.`)
+	flag.Float64Var(&cfg.Map.MaxOccurence, "map-occurence", cfg.Map.MaxOccurence,
+		`Maximum mean occurence ratio of members for an object to be considered
+a map.`)
 	flag.Parse()
+	if fConfig != "" {
+		if err := loadConfig(fConfig); err != nil {
+			log.Fatal(err)
+		}
+		flag.CommandLine.Parse(os.Args[1:])
+	}
+	if fShowCfg {
+		if err := writeConfig(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	var (
 		scm     = loadState(fState, &cfg)
 		samples int
 		err     error
 	)
+	if fGroupBy != "" {
+		scm = groupBy(scm, fGroupBy)
+	}
 
 	switch {
 	case fArgs == "-":
@@ -137,7 +231,7 @@ union variant. (env: `+envJsumUnionMerge+")\n")
 	for _, arg := range flag.Args() {
 		var n int
 		if arg == "-" {
-			dec := json.NewDecoder(os.Stdin)
+			dec := orderedDecoder{json.NewDecoder(os.Stdin)}
 			scm, n = read(dec, scm)
 		} else if scm, n, err = readFile(arg, scm); err != nil {
 			log.Fatal(err)
@@ -149,8 +243,21 @@ union variant. (env: `+envJsumUnionMerge+")\n")
 	if fState != "" && samples > 0 {
 		writeState(fState, scm)
 	}
+	scm = jsum.Discriminate(jsum.FoldRecursion(jsum.Compact(scm)))
+	var tdefs []jsum.TypeDef
+	if fTypes {
+		tdefs = jsum.NameTypes(scm)
+	}
 
-	if fOut == "" && fSchema == "" {
+	if fSchema != "" {
+		log.Println("writing JSON Schema to", fSchema)
+		writeDoc(fSchema, jsum.Schema, scm)
+	}
+	if fOpenAPI != "" {
+		log.Println("writing OpenAPI document to", fOpenAPI)
+		writeDoc(fOpenAPI, jsum.OpenAPI, scm)
+	}
+	if fOut == "" && fSchema == "" && fOpenAPI == "" {
 		log.Print("no output, no schema generation – staring interactive browser")
 		newBrowser(scm, samples).run()
 	} else if fOut != "" {
@@ -179,40 +286,70 @@ union variant. (env: `+envJsumUnionMerge+")\n")
 		sum := jsum.NewSummary(w, &jsum.SummaryConfig{
 			TreeStyle: tstyle,
 			StringMax: fStrMax,
+			ShapeMax:  fShapeMax,
 		})
 
-		if fSchema != "" {
-			scm := scm.JSONSchema()
-			f, err := os.Create(fSchema)
-			if err != nil {
+		if err := sum.Print(scm); err != nil {
+			log.Fatal(err)
+		}
+		if fNaming {
+			if err := sum.PrintNaming(scm); err != nil {
 				log.Fatal(err)
 			}
-			defer f.Close()
-			enc := json.NewEncoder(f)
-			enc.SetIndent("", "   ")
-			if err := enc.Encode(scm); err != nil {
+		}
+		if fSimilar > 0 {
+			if err := sum.PrintSimilar(scm, fSimilar); err != nil {
 				log.Fatal(err)
 			}
 		}
-
-		if err := sum.Print(scm); err != nil {
-			log.Fatal(err)
-		}
 		if fTypes {
-			dedup := make(jsum.DedupHash)
-			scm.Hash(dedup)
-			tdefs := dedup.ReusedTypes()
-			fmt.Fprintf(w, "\nFound %d distinct types\n", len(tdefs))
+			fmt.Fprintf(w, "\nFound %d reused types\n", len(tdefs))
 			for _, def := range tdefs {
-				head := fmt.Sprintf("\nOccurs %d times:", len(def.Copies())+1)
-				fmt.Println(head)
-				fmt.Println(strings.Repeat("-", len(head)-1))
-				sum.Print(def)
+				head := fmt.Sprintf("\n%s [%016x] occurs %d times: %s",
+					def.Name,
+					jsum.Fingerprint(def.Ded),
+					len(def.Paths),
+					strings.Join(def.Paths, ", "),
+				)
+				fmt.Fprintln(w, head)
+				fmt.Fprintln(w, strings.Repeat("-", utf8.RuneCountInString(head)-1))
+				sum.Print(def.Ded)
 			}
 		}
 	}
 }
 
+// writeDoc writes the document that gen generates from scm to file as JSON
+// or, depending on the file name extension, as YAML.
+func writeDoc(
+	file string,
+	gen func(jsum.Deducer, jsum.SchemaOptions) (map[string]any, error),
+	scm jsum.Deducer,
+) {
+	doc, err := gen(scm, fSchemaOpt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	switch filepath.Ext(file) {
+	case ".yml", ".yaml":
+		enc := yaml.NewEncoder(f)
+		enc.SetIndent(2)
+		err = enc.Encode(doc)
+	default:
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "   ")
+		err = enc.Encode(doc)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 func readArgsFile(file string, scm jsum.Deducer) (jsum.Deducer, int) {
 	r, err := os.Open(file)
 	if err != nil {
@@ -240,6 +377,19 @@ func readArgs(r io.Reader, scm jsum.Deducer) (_ jsum.Deducer, samples int) {
 
 type decoder interface{ Decode(any) error }
 
+// orderedDecoder reads JSON objects as jsum.OrderedObject to keep member order
+// and duplicate members.
+type orderedDecoder struct{ *json.Decoder }
+
+func (dec orderedDecoder) Decode(v any) error {
+	jv, err := jsum.Decode(dec.Decoder)
+	if err != nil {
+		return err
+	}
+	*v.(*any) = jv
+	return nil
+}
+
 func read(dec decoder, d jsum.Deducer) (jsum.Deducer, int) {
 	samples := 0
 	for {
@@ -276,7 +426,7 @@ func readFile(name string, d jsum.Deducer) (_ jsum.Deducer, n int, err error) {
 		d, n = read(dec, d)
 		return d, n, nil
 	}
-	dec := json.NewDecoder(rd)
+	dec := orderedDecoder{json.NewDecoder(rd)}
 	d, n = read(dec, d)
 	return d, n, nil
 }
@@ -306,6 +456,26 @@ func loadState(name string, cfg *jsum.Config) jsum.Deducer {
 	return state
 }
 
+func groupBy(scm jsum.Deducer, path string) jsum.Deducer {
+	switch scm := scm.(type) {
+	case *jsum.Unknown:
+		if scm.Count == 0 {
+			g, err := jsum.NewGrouped(&cfg, path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return g
+		}
+	case *jsum.Grouped:
+		if scm.Path == path {
+			return scm
+		}
+		log.Fatalf("state is grouped by %s, not %s", scm.Path, path)
+	}
+	log.Fatalf("cannot group by %s, state is not grouped", path)
+	return nil
+}
+
 func writeState(name string, scm jsum.Deducer) {
 	log.Println("write state", name)
 	f, err := os.Create(name + "~")
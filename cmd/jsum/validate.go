@@ -0,0 +1,116 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"git.fractalqb.de/fractalqb/jsum"
+	"gopkg.in/yaml.v3"
+)
+
+// validateMain runs the subcommand "jsum validate" that checks documents
+// against the summary in a state file. It exits with status 1 if there are
+// violations.
+func validateMain(args []string) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	state := flags.String("state", "", "State file with the summary to validate against")
+	flags.StringVar(&fConfig, "config", fConfig,
+		`Read configuration from JSON or YAML file, e.g. the one used to create
+the state file`)
+	flags.Usage = func() {
+		w := flags.Output()
+		fmt.Fprintln(w, `Check JSON or YAML documents against a summary from a state file.
+
+  Usage: jsum validate -state <file> [-config <file>] <JSON/YAML file>|'-'...
+
+Reports unknown and missing members, type mismatches, numbers and array
+lengths out of the observed range and unseen values with the JSON path and
+the record number.
+
+FLAGS:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *state == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if _, err := os.Stat(*state); err != nil {
+		log.Fatal(err)
+	}
+	if fConfig != "" {
+		if err := loadConfig(fConfig); err != nil {
+			log.Fatal(err)
+		}
+	}
+	scm := loadState(*state, &cfg)
+	scm = jsum.Discriminate(jsum.FoldRecursion(jsum.Compact(scm)))
+	violations := 0
+	for _, arg := range flags.Args() {
+		n, err := validateFile(arg, scm)
+		if err != nil {
+			log.Fatal(err)
+		}
+		violations += n
+	}
+	log.Printf("found %d violations", violations)
+	if violations > 0 {
+		os.Exit(1)
+	}
+}
+
+func validateFile(name string, scm jsum.Deducer) (int, error) {
+	var dec decoder
+	if name == "-" {
+		dec = orderedDecoder{json.NewDecoder(os.Stdin)}
+	} else {
+		rd, err := os.Open(name)
+		if err != nil {
+			return 0, err
+		}
+		defer rd.Close()
+		switch filepath.Ext(name) {
+		case ".yml", ".yaml":
+			dec = yaml.NewDecoder(rd)
+		default:
+			dec = orderedDecoder{json.NewDecoder(rd)}
+		}
+	}
+	violations := 0
+	for record := 1; ; record++ {
+		var jv any
+		switch err := dec.Decode(&jv); {
+		case err == io.EOF:
+			return violations, nil
+		case err != nil:
+			return violations, fmt.Errorf("%s record %d: %w", name, record, err)
+		}
+		for _, v := range jsum.Validate(scm, record, jv) {
+			fmt.Printf("%s: %s\n", name, v)
+			violations++
+		}
+	}
+}
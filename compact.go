@@ -0,0 +1,170 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Compact re-merges similar variants of all unions in d and orders the
+// variants by descending count. Union.Example assigns examples greedily in
+// the order they arrive. Compact merges variants with a similarity above
+// UnionConfig.MergeRejectMax, most similar pairs first, which makes the result
+// less dependent on the input order. Unions that are reduced to one variant
+// are replaced by that variant.
+func Compact(d Deducer) Deducer {
+	switch d := d.(type) {
+	case *Object:
+		for n, m := range d.Members {
+			m.Ded = Compact(m.Ded)
+			d.Members[n] = m
+		}
+	case *Array:
+		d.Elem = Compact(d.Elem)
+		for i, t := range d.Tuple {
+			d.Tuple[i] = Compact(t)
+		}
+	case *Map:
+		d.Value = Compact(d.Value)
+	case *Tagged:
+		for i := range d.Variants {
+			d.Variants[i].Ded = Compact(d.Variants[i].Ded)
+		}
+	case *Grouped:
+		for k, g := range d.Groups {
+			d.Groups[k] = Compact(g)
+		}
+	case *Union:
+		for i, v := range d.Variants {
+			d.Variants[i] = Compact(v)
+		}
+		return d.compact()
+	}
+	return d
+}
+
+func (u *Union) compact() Deducer {
+	byCount := func(a, b Deducer) int {
+		return cmp.Compare(b.super().Count, a.super().Count)
+	}
+	slices.SortStableFunc(u.Variants, byCount)
+	for {
+		mi, mj, smax := -1, -1, u.cfg.Union.MergeRejectMax
+		for i, a := range u.Variants {
+			for j := i + 1; j < len(u.Variants); j++ {
+				if s := Similarity(a, u.Variants[j]); s > smax {
+					mi, mj, smax = i, j, s
+				}
+			}
+		}
+		if mi < 0 {
+			break
+		}
+		u.Variants[mi] = merge(u.Variants[mi], u.Variants[mj])
+		u.Variants = slices.Delete(u.Variants, mj, mj+1)
+	}
+	slices.SortStableFunc(u.Variants, byCount)
+	if len(u.Variants) == 1 {
+		res := u.Variants[0]
+		res.super().Count = u.Count
+		res.super().Null = u.Null
+		return res
+	}
+	return u
+}
+
+// Similarity rates how similar a and b are from 0 (unrelated) to 1. Deducers
+// with different JSON types have similarity 0. Objects are rated by the sum
+// of the similarities of shared members divided by the number of all member
+// names. Shared members with values of the same JSON type have similarity 1,
+// or the Similarity for objects. A union member that has a variant of the
+// other member's type has similarity 0.5. With UnionConfig.AcceptAnyType all
+// shared members have similarity 1. With AcceptStrict only objects with the
+// same members are similar.
+func Similarity(a, b Deducer) float64 {
+	if a.JsonType() != b.JsonType() {
+		return 0
+	}
+	switch a := a.(type) {
+	case *Object:
+		if b, ok := b.(*Object); ok {
+			return objSimilarity(a, b)
+		}
+		return 0
+	case *Map:
+		if _, ok := b.(*Map); ok {
+			return 1
+		}
+		return 0
+	case *Tagged:
+		return 0
+	}
+	return 1
+}
+
+func objSimilarity(a, b *Object) float64 {
+	var sum float64
+	shared := 0
+	for n, am := range a.Members {
+		if bm, ok := b.Members[n]; ok {
+			shared++
+			if a.cfg.Union.AcceptAnyType {
+				sum++
+			} else {
+				sum += memberSimilarity(am.Ded, bm.Ded)
+			}
+		}
+	}
+	total := len(a.Members) + len(b.Members) - shared
+	if a.cfg.Union.Accept == AcceptStrict {
+		if sum == float64(total) {
+			return 1
+		}
+		return 0
+	}
+	if total == 0 {
+		return 1
+	}
+	return sum / float64(total)
+}
+
+func memberSimilarity(a, b Deducer) float64 {
+	if ao, ok := a.(*Object); ok {
+		if bo, ok := b.(*Object); ok {
+			return objSimilarity(ao, bo)
+		}
+	}
+	switch {
+	case a.JsonType() == b.JsonType():
+		return 1
+	case unionHas(a, b.JsonType()), unionHas(b, a.JsonType()):
+		return 0.5
+	}
+	return 0
+}
+
+func unionHas(d Deducer, jt JsonType) bool {
+	if u, ok := d.(*Union); ok {
+		return slices.ContainsFunc(u.Variants, func(v Deducer) bool {
+			return v.JsonType() == jt
+		})
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import "testing"
+
+func TestCompact(t *testing.T) {
+	cfg := Config{Union: UnionConfig{
+		MergeRejectMax: 0.5,
+		Combine:        []TypeSet{AllTypes},
+	}}
+	var d Deducer = NewUnknown(&cfg)
+	for _, v := range []any{
+		map[string]any{"x": 1.0},
+		map[string]any{"a": 1.0, "b": 1.0},
+		map[string]any{"a": 1.0, "c": 1.0},
+		map[string]any{"a": 1.0, "b": 1.0, "c": 1.0},
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	u, ok := d.(*Union)
+	if !ok {
+		t.Fatalf("deduced %T", d)
+	}
+	if l := len(u.Variants); l != 3 {
+		t.Fatalf("deduced %d variants", l)
+	}
+	d = Compact(d)
+	if u, ok = d.(*Union); !ok {
+		t.Fatalf("compacted to %T", d)
+	}
+	if l := len(u.Variants); l != 2 {
+		t.Fatalf("compacted to %d variants", l)
+	}
+	if o := u.Variants[0].(*Object); o.Count != 3 || len(o.Members) != 3 {
+		t.Errorf("unexpected 1st variant with %d members [%d×]", len(o.Members), o.Count)
+	}
+	if o := u.Variants[1].(*Object); o.Count != 1 || o.Members["x"].Ded == nil {
+		t.Errorf("unexpected 2nd variant with %d members [%d×]", len(o.Members), o.Count)
+	}
+}
+
+func TestCompact_single(t *testing.T) {
+	cfg := Config{Union: UnionConfig{
+		MergeRejectMax: 0.5,
+		Combine:        []TypeSet{AllTypes},
+	}}
+	var d Deducer = NewUnknown(&cfg)
+	for _, v := range []any{
+		map[string]any{"a": 1.0, "b": 1.0},
+		nil,
+		map[string]any{"a": 1.0, "c": 1.0},
+		map[string]any{"a": 1.0, "b": 1.0, "c": 1.0},
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	d = Compact(d)
+	o, ok := d.(*Object)
+	if !ok {
+		t.Fatalf("compacted to %T", d)
+	}
+	if o.Count != 4 || o.Null != 1 {
+		t.Errorf("unexpected counts %d / null %d", o.Count, o.Null)
+	}
+}
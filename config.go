@@ -18,6 +18,11 @@ along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 package jsum
 
+import (
+	"fmt"
+	"strings"
+)
+
 type DedupBool uint
 
 const (
@@ -25,6 +30,19 @@ const (
 	DedupBoolFalse
 )
 
+var dedupBoolNames = []string{"true", "false"}
+
+func (d DedupBool) String() string { return flagsString(uint(d), dedupBoolNames) }
+
+func (d DedupBool) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText reads criteria separated by '|' from: true, false.
+func (d *DedupBool) UnmarshalText(text []byte) error {
+	f, err := parseFlags(string(text), dedupBoolNames)
+	*d = DedupBool(f)
+	return err
+}
+
 type DedupNumber uint
 
 const (
@@ -36,30 +54,182 @@ const (
 	DedupNumberPos
 )
 
+var dedupNumberNames = []string{"int-float", "frac", "min", "max", "neg", "pos"}
+
+func (d DedupNumber) String() string { return flagsString(uint(d), dedupNumberNames) }
+
+func (d DedupNumber) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText reads criteria separated by '|' from: int-float, frac, min,
+// max, neg, pos.
+func (d *DedupNumber) UnmarshalText(text []byte) error {
+	f, err := parseFlags(string(text), dedupNumberNames)
+	*d = DedupNumber(f)
+	return err
+}
+
 type DedupString uint
 
 const (
 	DedupStringEmpty DedupString = 1 << iota
 )
 
+var dedupStringNames = []string{"empty"}
+
+func (d DedupString) String() string { return flagsString(uint(d), dedupStringNames) }
+
+func (d DedupString) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText reads criteria separated by '|' from: empty.
+func (d *DedupString) UnmarshalText(text []byte) error {
+	f, err := parseFlags(string(text), dedupStringNames)
+	*d = DedupString(f)
+	return err
+}
+
+func flagsString(f uint, names []string) string {
+	var sb strings.Builder
+	for i, n := range names {
+		if f&(1<<i) != 0 {
+			if sb.Len() > 0 {
+				sb.WriteByte('|')
+			}
+			sb.WriteString(n)
+		}
+	}
+	return sb.String()
+}
+
+func parseFlags(s string, names []string) (f uint, err error) {
+	if s == "" {
+		return 0, nil
+	}
+NEXT_FLAG:
+	for n := range strings.SplitSeq(s, "|") {
+		n = strings.TrimSpace(n)
+		for i, name := range names {
+			if n == name {
+				f |= 1 << i
+				continue NEXT_FLAG
+			}
+		}
+		return f, fmt.Errorf("unknown flag '%s'", n)
+	}
+	return f, nil
+}
+
 type Config struct {
-	Union UnionConfig
-	Dedup DedupConfig
+	Union  UnionConfig  `json:"union"`
+	Dedup  DedupConfig  `json:"dedup"`
+	Map    MapConfig    `json:"map"`
+	Object ObjectConfig `json:"object"`
+	Array  ArrayConfig  `json:"array"`
+}
+
+type ArrayConfig struct {
+	// MaxTuple is the maximum length of arrays that are checked to be tuples,
+	// see Array.Tuple. Zero disables tuple detection.
+	MaxTuple int `json:"max-tuple"`
 }
 
 type UnionConfig struct {
 	// MergeRejectMax is the maximum acceptance that will be rejected to be
 	// merged into an existing variant. New values with a better acceptance will
 	// be merged into the best accepting variant.
-	MergeRejectMax float64
+	MergeRejectMax float64 `json:"merge-reject-max"`
+
+	// Accept selects how the acceptance of an example object by an object
+	// variant is computed.
+	Accept Acceptance `json:"accept"`
+
+	// AcceptAnyType makes members with the same name count as common members
+	// even if their values have different JSON types.
+	AcceptAnyType bool `json:"accept-any-type"`
 
 	// Combine is a set of JsonType combinations that are allowed to coexist as
 	// variants in a union.
-	Combine []TypeSet
+	Combine []TypeSet `json:"combine"`
+
+	// Tags are names of string members that discriminate object variants. An
+	// object with one of these members is deduced as a Tagged union with one
+	// variant per tag value.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Acceptance is a strategy to rate how well an example object fits an object
+// variant, see UnionConfig.Accept.
+type Acceptance int
+
+const (
+	// AcceptJaccard is the number of common members divided by the number of
+	// all members of the object and the example.
+	AcceptJaccard Acceptance = iota
+
+	// AcceptWeighted is like AcceptJaccard but the object's members are
+	// weighted by the ratio of objects they occured in. Missing rare members
+	// decrease the acceptance less than missing frequent members.
+	AcceptWeighted
+
+	// AcceptStrict only accepts examples with exactly the members of the
+	// object.
+	AcceptStrict
+)
+
+var acceptanceNames = []string{
+	AcceptJaccard:  "jaccard",
+	AcceptWeighted: "weighted",
+	AcceptStrict:   "strict",
+}
+
+func (a Acceptance) String() string {
+	if a >= 0 && int(a) < len(acceptanceNames) {
+		return acceptanceNames[a]
+	}
+	return fmt.Sprintf("Acceptance(%d)", int(a))
+}
+
+func (a Acceptance) MarshalText() ([]byte, error) {
+	if a < 0 || int(a) >= len(acceptanceNames) {
+		return nil, fmt.Errorf("invalid acceptance %d", int(a))
+	}
+	return []byte(acceptanceNames[a]), nil
+}
+
+func (a *Acceptance) UnmarshalText(text []byte) error {
+	for i, n := range acceptanceNames {
+		if n == string(text) {
+			*a = Acceptance(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown acceptance '%s'", text)
+}
+
+type ObjectConfig struct {
+	// MaxShapes is the maximum number of distinct member sets that are tracked
+	// per object to analyse member co-occurence. Zero disables tracking.
+	MaxShapes int `json:"max-shapes"`
+
+	// MaxOrders is the maximum number of distinct member orders that are
+	// tracked per object. Member order is only known for OrderedObject
+	// examples. Zero disables tracking.
+	MaxOrders int `json:"max-orders"`
+}
+
+// MapConfig controls the detection of objects that are used as maps, i.e.
+// objects with dynamic member names.
+type MapConfig struct {
+	// MinMembers is the minimum number of distinct members an object must have
+	// to be considered a map. Zero disables map detection.
+	MinMembers int `json:"min-members"`
+
+	// MaxOccurence is the maximum mean ratio of objects a member occurs in for
+	// the object to be considered a map.
+	MaxOccurence float64 `json:"max-occurence"`
 }
 
 type DedupConfig struct {
-	Bool   DedupBool
-	Number DedupNumber
-	String DedupString
+	Bool   DedupBool   `json:"bool"`
+	Number DedupNumber `json:"number"`
+	String DedupString `json:"string"`
 }
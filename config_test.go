@@ -0,0 +1,90 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTypeSet_text(t *testing.T) {
+	for _, ts := range []TypeSet{
+		0,
+		AllTypes,
+		NewTypeSet(JsonString, JsonNumber, JsonBoolean),
+		NewTypeSet(JsonObject),
+	} {
+		txt, _ := ts.MarshalText()
+		var rd TypeSet
+		if err := rd.UnmarshalText(txt); err != nil {
+			t.Fatal(err)
+		}
+		if rd != ts {
+			t.Errorf("'%s' read as '%s'", txt, rd)
+		}
+	}
+	var ts TypeSet
+	if err := ts.UnmarshalText([]byte("string|text")); err == nil {
+		t.Error("no error for unknown JSON type")
+	}
+}
+
+func TestDedup_text(t *testing.T) {
+	var n DedupNumber
+	if err := n.UnmarshalText([]byte("min|neg")); err != nil {
+		t.Fatal(err)
+	}
+	if n != DedupNumberMin|DedupNumberNeg {
+		t.Errorf("unexpected number criteria %s", n)
+	}
+	if err := n.UnmarshalText([]byte("min|zero")); err == nil {
+		t.Error("no error for unknown criterion")
+	}
+}
+
+func TestConfig_json(t *testing.T) {
+	cfg := Config{
+		Union: UnionConfig{
+			MergeRejectMax: 0.25,
+			Accept:         AcceptWeighted,
+			Combine:        []TypeSet{NewTypeSet(JsonString, JsonNumber), AllTypes},
+			Tags:           []string{"kind"},
+		},
+		Dedup: DedupConfig{
+			Bool:   DedupBoolTrue,
+			Number: DedpuNumberIntFloat | DedupNumberPos,
+			String: DedupStringEmpty,
+		},
+		Map:    MapConfig{MinMembers: 16, MaxOccurence: 0.2},
+		Object: ObjectConfig{MaxShapes: 8, MaxOrders: 4},
+		Array:  ArrayConfig{MaxTuple: 5},
+	}
+	data, err := json.Marshal(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rd Config
+	if err := json.Unmarshal(data, &rd); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rd, cfg) {
+		t.Errorf("read %+v from %s", rd, data)
+	}
+}
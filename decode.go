@@ -0,0 +1,220 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// OrderedObject is a JSON object that keeps the order of its members including
+// members with duplicate names. Use Decode to read JSON values with objects
+// as OrderedObject.
+type OrderedObject []ObjectMember
+
+type ObjectMember struct {
+	Name  string
+	Value any
+}
+
+// Get returns the value of the last member with the given name, like
+// encoding/json would do when decoding into a map.
+func (o OrderedObject) Get(name string) (v any, ok bool) {
+	for i := len(o) - 1; i >= 0; i-- {
+		if o[i].Name == name {
+			return o[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+func (o OrderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		n, err := json.Marshal(m.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(n)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Decode reads the next JSON value from dec. Other than dec.Decode it reads
+// objects as OrderedObject. Decode returns io.EOF if there is no more value
+// to read.
+func Decode(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return decodeTok(dec, tok)
+}
+
+func decodeTok(dec *json.Decoder, tok json.Token) (any, error) {
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			return decodeObj(dec)
+		case '[':
+			return decodeArr(dec)
+		}
+		return nil, fmt.Errorf("unexpected delimiter '%s'", tok)
+	case json.Number:
+		return tok.Float64()
+	}
+	return tok, nil
+}
+
+func decodeObj(dec *json.Decoder) (OrderedObject, error) {
+	res := OrderedObject{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("object member name is %T", tok)
+		}
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		v, err := decodeTok(dec, tok)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ObjectMember{Name: name, Value: v})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, noEOF(err)
+	}
+	return res, nil
+}
+
+func decodeArr(dec *json.Decoder) ([]any, error) {
+	res := []any{}
+	for dec.More() {
+		v, err := Decode(dec)
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		res = append(res, v)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, noEOF(err)
+	}
+	return res, nil
+}
+
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func objOrderedSeq(v any) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, m := range v.(OrderedObject) {
+			if !yield(m.Name, m.Value) {
+				return
+			}
+		}
+	}
+}
+
+// objSeq returns the members of the object example v. If v is no supported
+// object nil is returned.
+func objSeq(v any, jt JsumType) iter.Seq2[string, any] {
+	switch jt.v {
+	case jsonObjStrAny:
+		return objStrAnySeq(v)
+	case jsonObjOrdered:
+		return objOrderedSeq(v)
+	case jsonObjRMap:
+		if reflect.TypeOf(v).Key().Kind() == reflect.String {
+			return objRMapSeq(v)
+		}
+	}
+	return nil
+}
+
+func objRMapSeq(v any) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for it := reflect.ValueOf(v).MapRange(); it.Next(); {
+			if !yield(it.Key().String(), it.Value().Interface()) {
+				return
+			}
+		}
+	}
+}
+
+// arrElems returns the elements of the array example v and whether v is a
+// supported array.
+func arrElems(v any, jt JsumType) ([]any, bool) {
+	switch jt.v {
+	case jsonArrAny:
+		return v.([]any), true
+	case jsonArrRSlice:
+		rv := reflect.ValueOf(v)
+		res := make([]any, rv.Len())
+		for i := range res {
+			res[i] = rv.Index(i).Interface()
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// objMember returns the value of the member name of the object example v.
+func objMember(v any, name string) (any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		m, ok := v[name]
+		return m, ok
+	case OrderedObject:
+		return v.Get(name)
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		m := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if m.IsValid() {
+			return m.Interface(), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,90 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"git.fractalqb.de/fractalqb/testerr"
+)
+
+func TestDecode(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(
+		`{"b":1,"a":[true,null,"x"],"b":{}} 4711 {"a`,
+	))
+	v := testerr.Shall1(Decode(dec)).BeNil(t)
+	exp := OrderedObject{
+		{"b", 1.0},
+		{"a", []any{true, nil, "x"}},
+		{"b", OrderedObject{}},
+	}
+	if !reflect.DeepEqual(v, exp) {
+		t.Fatalf("unexpected value %#v", v)
+	}
+	if b, _ := exp.Get("b"); !reflect.DeepEqual(b, OrderedObject{}) {
+		t.Errorf("get returned not the last member but %#v", b)
+	}
+	v = testerr.Shall1(Decode(dec)).BeNil(t)
+	if v != 4711.0 {
+		t.Errorf("unexpected number %#v", v)
+	}
+	if _, err := Decode(dec); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("unexpected error for truncated object: %v", err)
+	}
+}
+
+func TestObject_orderAndDuplicates(t *testing.T) {
+	cfg := Config{Object: ObjectConfig{MaxShapes: 8, MaxOrders: 8}}
+	var d Deducer = NewUnknown(&cfg)
+	for _, js := range []string{
+		`{"id":1,"name":"a","x":1}`,
+		`{"id":2,"x":2,"name":"b","name":"c","name":"d"}`,
+		`{"id":3,"name":"e"}`,
+	} {
+		v := testerr.Shall1(Decode(json.NewDecoder(strings.NewReader(js)))).BeNil(t)
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	o, ok := d.(*Object)
+	if !ok {
+		t.Fatalf("deduced not object but %T", d)
+	}
+	if o.Duplicates != 1 {
+		t.Errorf("unexpected duplicates count %d", o.Duplicates)
+	}
+	if m := o.Members["name"]; m.Occurence != 3 || m.Duplicates != 1 {
+		t.Errorf("unexpected name member %+v", m)
+	}
+	if l := len(o.Orders); l != 3 {
+		t.Errorf("unexpected number of orders %d", l)
+	}
+	if _, ok := o.MemberOrder(); ok {
+		t.Error("inconsistent order not detected")
+	}
+	delete(o.Orders, "id"+shapeSep+"x"+shapeSep+"name")
+	if order, ok := o.MemberOrder(); !ok {
+		t.Error("consistent order not detected")
+	} else if !reflect.DeepEqual(order, []string{"id", "name", "x"}) {
+		t.Errorf("unexpected canonical order %v", order)
+	}
+}
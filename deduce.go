@@ -20,8 +20,13 @@ package jsum
 
 import (
 	"encoding/binary"
-	"hash/maphash"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"maps"
 	"reflect"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -53,6 +58,42 @@ const (
 	jsonInvalid
 )
 
+var jsonTypeNames = [...]string{
+	JsonNull:    "null",
+	JsonObject:  "object",
+	JsonArray:   "array",
+	JsonString:  "string",
+	JsonNumber:  "number",
+	JsonBoolean: "boolean",
+	JsonUnknown: "unknown",
+	JsonUnion:   "union",
+	JsonAny:     "any",
+}
+
+func (jt JsonType) String() string {
+	if jt > 0 && jt < jsonInvalid {
+		return jsonTypeNames[jt]
+	}
+	return "invalid"
+}
+
+func (jt JsonType) MarshalText() ([]byte, error) {
+	if jt > 0 && jt < jsonInvalid {
+		return []byte(jsonTypeNames[jt]), nil
+	}
+	return nil, fmt.Errorf("invalid JSON type %d", jt)
+}
+
+func (jt *JsonType) UnmarshalText(text []byte) error {
+	for t := JsonNull; t < jsonInvalid; t++ {
+		if jsonTypeNames[t] == string(text) {
+			*jt = t
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown JSON type '%s'", text)
+}
+
 func (jt JsonType) scalar() bool {
 	return jt >= JsonString && jt <= JsonBoolean
 }
@@ -74,6 +115,48 @@ func (ts *TypeSet) Add(t JsonType) {
 	}
 }
 
+func (ts TypeSet) Has(t JsonType) bool {
+	return t > 0 && t < jsonInvalid && ts&(1<<(t-1)) != 0
+}
+
+// String returns the names of the JSON types in ts separated by '|' or "all"
+// for AllTypes.
+func (ts TypeSet) String() string {
+	if ts == AllTypes {
+		return "all"
+	}
+	var names []string
+	for t := JsonNull; t < jsonInvalid; t++ {
+		if ts.Has(t) {
+			names = append(names, jsonTypeNames[t])
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+func (ts TypeSet) MarshalText() ([]byte, error) { return []byte(ts.String()), nil }
+
+// UnmarshalText reads JSON type names separated by '|', e.g.
+// "string|number|boolean", or "all" for AllTypes.
+func (ts *TypeSet) UnmarshalText(text []byte) error {
+	if string(text) == "all" {
+		*ts = AllTypes
+		return nil
+	}
+	var set TypeSet
+	if len(text) > 0 {
+		for n := range strings.SplitSeq(string(text), "|") {
+			var t JsonType
+			if err := t.UnmarshalText([]byte(strings.TrimSpace(n))); err != nil {
+				return err
+			}
+			set.Add(t)
+		}
+	}
+	*ts = set
+	return nil
+}
+
 const (
 	jsonStrString jsonVariant = iota
 	jsonStrTime
@@ -91,6 +174,7 @@ const (
 	jsonNumFloat64
 	jsonObjRMap
 	jsonObjStrAny
+	jsonObjOrdered
 	jsonArrAny
 	jsonArrRSlice
 )
@@ -132,6 +216,8 @@ func JsonTypeOf(v any) JsumType {
 		return JsumType{t: JsonString, v: jsonStrTime}
 	case map[string]any:
 		return JsumType{t: JsonObject, v: jsonObjStrAny}
+	case OrderedObject:
+		return JsumType{t: JsonObject, v: jsonObjOrdered}
 	case []any:
 		return JsumType{t: JsonArray, v: jsonArrAny}
 	}
@@ -145,11 +231,22 @@ func JsonTypeOf(v any) JsumType {
 	return JsumType{}
 }
 
+// DedupHash collects deducers by their Hash to find equal types. Hash values
+// only depend on the structure of a deducer, i.e. they are the same across
+// runs and machines and can be stored as type fingerprints.
 type DedupHash map[uint64][]Deducer
 
+func (dh DedupHash) add(h uint64, d Deducer) {
+	if dh != nil {
+		dh[h] = addNotEqual(dh[h], d)
+	}
+}
+
+// ReusedTypes returns the deducers that have equal copies, ordered by hash
+// value.
 func (dh DedupHash) ReusedTypes() (res []Deducer) {
-	for _, d := range dh {
-		for _, t := range d {
+	for _, h := range slices.Sorted(maps.Keys(dh)) {
+		for _, t := range dh[h] {
 			if len(t.super().copies) > 0 {
 				res = append(res, t)
 			}
@@ -168,7 +265,7 @@ type Deducer interface {
 	Hash(dh DedupHash) uint64
 	Copies() []Deducer
 	Equal(d Deducer) bool
-	JSONSchema() any
+	JSONSchema(opts *SchemaOptions) any
 	super() *dedBase
 }
 
@@ -178,15 +275,31 @@ type dedBase struct {
 	Null   int `json:"null,omitempty"`
 	orig   Deducer
 	copies []Deducer
+	// typeName is set by NameTypes for reused types
+	typeName string
 }
 
 func (d *dedBase) Nulls() int { return d.Null }
 
 func (d *dedBase) Copies() []Deducer { return d.copies }
 
-func (d *dedBase) startHash(jt JsonType) *maphash.Hash {
-	h := new(maphash.Hash)
-	h.SetSeed(hashSeed)
+// typeHash is the 64-bit FNV-1a hash used for deducer hashes.
+type typeHash struct{ hash.Hash64 }
+
+func (h typeHash) WriteByte(b byte) error {
+	_, err := h.Write([]byte{b})
+	return err
+}
+
+// WriteString writes s with its length so that consecutive strings cannot
+// produce the same input.
+func (h typeHash) WriteString(s string) (int, error) {
+	binary.Write(h, hashEndian, uint32(len(s)))
+	return h.Write([]byte(s))
+}
+
+func (d *dedBase) startHash(jt JsonType) typeHash {
+	h := typeHash{fnv.New64a()}
 	binary.Write(h, hashEndian, int32(jt))
 	if d.Null > 0 {
 		h.WriteByte(0)
@@ -196,8 +309,11 @@ func (d *dedBase) startHash(jt JsonType) *maphash.Hash {
 	return h
 }
 
+// Equal reports whether lhs and rhs are both nullable or both not. Like the
+// Hash methods, Equal implementations of deducers compare the structure of
+// types, not their statistics: equal deducers have the same hash.
 func (lhs *dedBase) Equal(rhs *dedBase) bool {
-	return lhs.Null == rhs.Null
+	return (lhs.Null > 0) == (rhs.Null > 0)
 }
 
 func Deduce(cfg *Config, v any) Deducer {
@@ -205,10 +321,11 @@ func Deduce(cfg *Config, v any) Deducer {
 	return tmp.Example(v, JsonTypeOf(v), UnknownAccept)
 }
 
-var (
-	hashEndian = binary.LittleEndian
-	hashSeed   = maphash.MakeSeed()
-)
+var hashEndian = binary.LittleEndian
+
+// Fingerprint returns the hash of the structure of d. Equal types have the
+// same fingerprint in every run, given the same Config.Dedup settings.
+func Fingerprint(d Deducer) uint64 { return d.Hash(nil) }
 
 func addNotEqual(ds []Deducer, d Deducer) []Deducer {
 	for _, e := range ds {
@@ -18,6 +18,8 @@ along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 package jsum
 
+import "testing"
+
 var (
 	_ Deducer = (*Unknown)(nil)
 	_ Deducer = (*Object)(nil)
@@ -25,7 +27,54 @@ var (
 	_ Deducer = (*Number)(nil)
 	_ Deducer = (*Boolean)(nil)
 	_ Deducer = (*String)(nil)
+	_ Deducer = (*Map)(nil)
 	_ Deducer = (*Union)(nil)
+	_ Deducer = (*Tagged)(nil)
+	_ Deducer = (*Grouped)(nil)
+	_ Deducer = (*Recursion)(nil)
 	_ Deducer = (*Any)(nil)
 	_ Deducer = Invalid{}
 )
+
+func TestFingerprint(t *testing.T) {
+	v := map[string]any{"a": "x", "b": []any{1.0, 2.0}, "c": nil}
+	fp := Fingerprint(Deduce(&testCfg, v))
+	if fp2 := Fingerprint(Deduce(&testCfg, v)); fp2 != fp {
+		t.Errorf("fingerprints differ: %x / %x", fp, fp2)
+	}
+	// Fingerprints must be stable across runs and machines
+	if fp != 0xe5c7eee24d88b1fa {
+		t.Errorf("unexpected fingerprint %x", fp)
+	}
+	w := map[string]any{"a": "x", "b": []any{1.0, 2.0}, "d": nil}
+	if fpw := Fingerprint(Deduce(&testCfg, w)); fpw == fp {
+		t.Error("member names do not change the fingerprint")
+	}
+}
+
+func TestDedupHash_ReusedTypes(t *testing.T) {
+	obj := func(n string) map[string]any { return map[string]any{n: "x"} }
+	v := map[string]any{
+		"a": obj("x"), "b": obj("x"),
+		"c": obj("y"), "d": obj("y"),
+		"e": obj("z"), "f": obj("z"),
+	}
+	d := Deduce(&testCfg, v)
+	dh := make(DedupHash)
+	d.Hash(dh)
+	reused := dh.ReusedTypes()
+	objs := 0
+	for _, r := range reused {
+		if _, ok := r.(*Object); ok {
+			objs++
+		}
+	}
+	if objs != 3 {
+		t.Fatalf("found %d reused objects", objs)
+	}
+	for i := 1; i < len(reused); i++ {
+		if Fingerprint(reused[i-1]) >= Fingerprint(reused[i]) {
+			t.Errorf("reused types not ordered by hash: %d", i)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import "testing"
+
+func deduceAll(cfg *Config, vs ...any) Deducer {
+	var d Deducer = NewUnknown(cfg)
+	for _, v := range vs {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	return d
+}
+
+func TestDeducer_Equal(t *testing.T) {
+	union := Config{Union: UnionConfig{Combine: []TypeSet{AllTypes}}}
+	tagged := Config{Union: UnionConfig{Tags: []string{"kind"}}}
+	dedup := Config{Dedup: DedupConfig{
+		Bool:   DedupBoolTrue | DedupBoolFalse,
+		String: DedupStringEmpty,
+	}}
+	type obj = map[string]any
+	tests := []struct {
+		name  string
+		cfg   *Config
+		a, b  []any
+		equal bool
+	}{
+		{"null counts", &testCfg,
+			[]any{"a", nil}, []any{"b", nil, nil}, true},
+		{"nullable", &testCfg,
+			[]any{"a", nil}, []any{"b"}, false},
+		{"string format", &testCfg,
+			[]any{"2025-01-02T03:04:05Z"}, []any{"foo"}, false},
+		{"empty string ignored", &testCfg,
+			[]any{""}, []any{"foo"}, true},
+		{"empty string", &dedup,
+			[]any{""}, []any{"foo"}, false},
+		{"bool values ignored", &testCfg,
+			[]any{true}, []any{false}, true},
+		{"bool values", &dedup,
+			[]any{true}, []any{false}, false},
+		{"union order", &union,
+			[]any{"a", 1.0}, []any{2.0, "b"}, true},
+		{"union variants", &union,
+			[]any{"a", 1.0}, []any{"b", true}, false},
+		{"member names", &testCfg,
+			[]any{obj{"a": "x"}}, []any{obj{"b": "x"}}, false},
+		{"member types", &testCfg,
+			[]any{obj{"a": "x"}}, []any{obj{"a": 1.0}}, false},
+		{"optional member", &testCfg,
+			[]any{obj{"a": "x", "b": "y"}, obj{"a": "x"}},
+			[]any{obj{"a": "x", "b": "y"}},
+			false},
+		{"member order", &testCfg,
+			[]any{obj{"a": "x", "b": 1.0}}, []any{obj{"b": 2.0, "a": "y"}}, true},
+		{"tagged order", &tagged,
+			[]any{obj{"kind": "a", "x": 1.0}, obj{"kind": "b", "s": "s"}},
+			[]any{obj{"kind": "b", "s": "t"}, obj{"kind": "a", "x": 2.0}},
+			true},
+		{"tagged values", &tagged,
+			[]any{obj{"kind": "a", "x": 1.0}, obj{"kind": "b", "s": "s"}},
+			[]any{obj{"kind": "a", "x": 1.0}, obj{"kind": "c", "s": "s"}},
+			false},
+		{"array elements", &testCfg,
+			[]any{[]any{"a"}}, []any{[]any{1.0}}, false},
+		{"empty array", &testCfg,
+			[]any{[]any{"a"}, []any{}}, []any{[]any{"a"}}, false},
+		{"any types", &testCfg,
+			[]any{"a", 1.0}, []any{"b", 2.0, 3.0}, true},
+		{"any type sets", &testCfg,
+			[]any{"a", 1.0}, []any{"b", true}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := deduceAll(test.cfg, test.a...)
+			b := deduceAll(test.cfg, test.b...)
+			if eq := a.Equal(b); eq != test.equal {
+				t.Errorf("a equals b: %t", eq)
+			}
+			if eq := b.Equal(a); eq != test.equal {
+				t.Errorf("b equals a: %t", eq)
+			}
+			if test.equal && Fingerprint(a) != Fingerprint(b) {
+				t.Error("equal deducers have different hashes")
+			}
+		})
+	}
+}
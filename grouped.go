@@ -0,0 +1,168 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Grouped keeps a separate deducer for each distinct value found at Path in
+// the examples. Use it as the root deducer to e.g. get one summary per event
+// type of an event stream.
+type Grouped struct {
+	dedBase
+	Path   string             `json:"path"`
+	Groups map[string]Deducer `json:"groups"`
+	path   []string
+}
+
+// GroupMissing is the group key of examples that have no value at the group
+// path.
+const GroupMissing = ""
+
+// NewGrouped creates a deducer that groups examples by the value at path. The
+// path is a sequence of member names separated by '.' and may start with '$',
+// e.g. "$.type" or "$.meta.kind".
+func NewGrouped(cfg *Config, path string) (*Grouped, error) {
+	p, err := parseGroupPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Grouped{
+		dedBase: dedBase{cfg: cfg},
+		Path:    path,
+		Groups:  make(map[string]Deducer),
+		path:    p,
+	}, nil
+}
+
+func parseGroupPath(path string) ([]string, error) {
+	p := strings.TrimPrefix(path, "$")
+	p = strings.TrimPrefix(p, ".")
+	if p == "" {
+		return nil, errors.New("empty group path")
+	}
+	res := strings.Split(p, ".")
+	if slices.Contains(res, "") {
+		return nil, errors.New("empty member name in group path " + path)
+	}
+	return res, nil
+}
+
+// GroupKey returns the group key of the example v. The key is the JSON
+// encoding of the value at the group path or GroupMissing.
+func (g *Grouped) GroupKey(v any) string {
+	if g.path == nil {
+		g.path, _ = parseGroupPath(g.Path)
+	}
+	for _, n := range g.path {
+		var ok bool
+		if v, ok = objMember(v, n); !ok {
+			return GroupMissing
+		}
+	}
+	key, err := json.Marshal(v)
+	if err != nil {
+		return GroupMissing
+	}
+	return string(key)
+}
+
+// GroupKeys returns the sorted keys of all groups.
+func (g *Grouped) GroupKeys() []string {
+	return slices.Sorted(maps.Keys(g.Groups))
+}
+
+func (*Grouped) JsonType() JsonType { return JsonUnion }
+
+func (*Grouped) Accepts(any, JsumType) float64 { return 1 }
+
+func (g *Grouped) Example(v any, jt JsumType, acpt float64) Deducer {
+	g.Count++
+	if jt.t == JsonNull {
+		g.Null++
+	}
+	key := g.GroupKey(v)
+	d, ok := g.Groups[key]
+	if !ok {
+		d = NewUnknown(g.cfg)
+	}
+	g.Groups[key] = d.Example(v, jt, acpt)
+	return g
+}
+
+func (g *Grouped) mergeGrouped(b *Grouped) *Grouped {
+	g.Count += b.Count
+	g.Null += b.Null
+	for k, bd := range b.Groups {
+		if d, ok := g.Groups[k]; ok {
+			g.Groups[k] = merge(d, bd)
+		} else {
+			g.Groups[k] = bd
+		}
+	}
+	return g
+}
+
+func (g *Grouped) Hash(dh DedupHash) uint64 {
+	hash := g.dedBase.startHash(JsonUnion)
+	hash.WriteString(g.Path)
+	for _, k := range g.GroupKeys() {
+		hash.WriteString(k)
+		binary.Write(hash, hashEndian, g.Groups[k].Hash(dh))
+	}
+	res := hash.Sum64()
+	dh.add(res, g)
+	return res
+}
+
+func (g *Grouped) Equal(d Deducer) bool {
+	b, ok := d.(*Grouped)
+	if !ok {
+		return false
+	}
+	if !g.dedBase.Equal(&b.dedBase) || g.Path != b.Path || len(g.Groups) != len(b.Groups) {
+		return false
+	}
+	for k, gd := range g.Groups {
+		bd, ok := b.Groups[k]
+		if !ok || !gd.Equal(bd) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Grouped) JSONSchema(opts *SchemaOptions) any {
+	scm := jscmAnyOf{AnyOf: make([]any, 0, len(g.Groups))}
+	for _, k := range g.GroupKeys() {
+		scm.AnyOf = append(scm.AnyOf, schemaOf(g.Groups[k], opts))
+	}
+	if g.Null > 0 {
+		scm.AnyOf = append(scm.AnyOf, jscmType{Type: "null"})
+	}
+	return scm
+}
+
+func (g *Grouped) super() *dedBase { return &g.dedBase }
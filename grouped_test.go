@@ -0,0 +1,59 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGrouped_Example(t *testing.T) {
+	g, err := NewGrouped(&testCfg, "$.meta.type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Deducer = g
+	for _, v := range []any{
+		map[string]any{"meta": map[string]any{"type": "a"}, "x": 1.0},
+		map[string]any{"meta": map[string]any{"type": "b"}, "s": "foo"},
+		map[string]any{"meta": map[string]any{"type": "a"}, "x": 2.0},
+		map[string]any{"meta": map[string]any{"type": 1.0}},
+		map[string]any{"x": 3.0},
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	if d != g {
+		t.Fatalf("grouped deducer replaced by %T", d)
+	}
+	keys := g.GroupKeys()
+	if !slices.Equal(keys, []string{GroupMissing, `"a"`, `"b"`, "1"}) {
+		t.Fatalf("unexpected group keys %q", keys)
+	}
+	if c := g.Groups[`"a"`].super().Count; c != 2 {
+		t.Errorf("unexpected count %d of group a", c)
+	}
+}
+
+func TestNewGrouped_path(t *testing.T) {
+	for _, p := range []string{"", "$", "$.", "$.a..b", "a."} {
+		if _, err := NewGrouped(&testCfg, p); err == nil {
+			t.Errorf("no error for path '%s'", p)
+		}
+	}
+}
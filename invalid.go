@@ -38,7 +38,7 @@ func (Invalid) Equal(_ Deducer) bool { return false }
 
 func (Invalid) Copies() []Deducer { return nil }
 
-func (i Invalid) JSONSchema() any {
+func (i Invalid) JSONSchema(*SchemaOptions) any {
 	return i.error // TODO / OK?
 }
 
@@ -1,27 +1,50 @@
 package jsum
 
 type jscmType struct {
-	Type string `json:"type"`
+	Type any `json:"type"`
+}
+
+// schemaType returns the type keyword for JSON type t that also allows null
+// if null is true.
+func schemaType(t string, null bool) jscmType {
+	if null {
+		return jscmType{Type: []string{t, "null"}}
+	}
+	return jscmType{Type: t}
+}
+
+// nullable returns scm or, if null is true, a schema that also allows null.
+// This is for schemas that have no type keyword.
+func nullable(scm any, null bool) any {
+	if null {
+		return jscmAnyOf{AnyOf: []any{scm, jscmType{Type: "null"}}}
+	}
+	return scm
 }
 
 type jscmNumber struct {
 	jscmType
 	Min *float64 `json:"minimum,omitempty"`
 	Max *float64 `json:"maximum,omitempty"`
+
+	MultipleOf *float64 `json:"multipleOf,omitempty"`
 }
 
 type jscmString struct {
 	jscmType
-	Format string `json:"format,omitempty"`
-	MinLen *int   `json:"minLength,omitempty"`
-	MaxLen *int   `json:"maxLength,omitempty"`
+	Format  string `json:"format,omitempty"`
+	MinLen  *int   `json:"minLength,omitempty"`
+	MaxLen  *int   `json:"maxLength,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
 }
 
 type jscmArray struct {
 	jscmType
-	MinItems int `json:"minItems"`
-	MaxItems int `json:"maxItems"`
-	Items    any `json:"items,omitempty"`
+	MinItems    *int  `json:"minItems,omitempty"`
+	MaxItems    *int  `json:"maxItems,omitempty"`
+	PrefixItems []any `json:"prefixItems,omitempty"`
+	Items       any   `json:"items,omitempty"`
+	UniqueItems bool  `json:"uniqueItems,omitempty"`
 }
 
 type jscmAnyOf struct {
@@ -30,6 +53,40 @@ type jscmAnyOf struct {
 
 type jscmObj struct {
 	jscmType
-	Required []string       `json:"required,omitempty"`
-	Props    map[string]any `json:"properties"`
+	Required   []string       `json:"required,omitempty"`
+	Props      map[string]any `json:"properties"`
+	Additional any            `json:"additionalProperties,omitempty"`
+}
+
+type jscmMap struct {
+	jscmType
+	Additional any            `json:"additionalProperties"`
+	PropNames  *jscmPropNames `json:"propertyNames,omitempty"`
+}
+
+type jscmPropNames struct {
+	Pattern string `json:"pattern"`
+}
+
+type jscmOneOf struct {
+	OneOf         []any              `json:"oneOf"`
+	Discriminator *jscmDiscriminator `json:"discriminator,omitempty"`
+}
+
+type jscmDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+type jscmConst struct {
+	Const any `json:"const"`
+}
+
+type jscmEnum struct {
+	jscmType
+	Enum []string `json:"enum"`
+}
+
+type jscmRef struct {
+	Ref string `json:"$ref"`
 }
@@ -0,0 +1,392 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/binary"
+	"iter"
+	"math"
+	"unicode/utf8"
+)
+
+// Map is deduced for objects that are used as dictionaries, i.e. objects with
+// dynamic member names like IDs, dates or hashes. All member values are
+// merged into the one Value deducer.
+type Map struct {
+	dedBase
+	Entries int `json:"entries"`
+	MinLen  int `json:"min-key-len"`
+	MaxLen  int `json:"max-key-len"`
+	// Pattern is the set of patterns that all keys match. It is empty if no
+	// key was seen.
+	Pattern KeyPattern `json:"key-pattern"`
+	Value   Deducer    `json:"values"`
+}
+
+// KeyPattern is a set of patterns that are matched by map keys.
+type KeyPattern uint
+
+const (
+	KeyInteger KeyPattern = 1 << iota
+	KeyHex
+	KeyUUID
+	KeyDate
+
+	keyAllPatterns = KeyInteger | KeyHex | KeyUUID | KeyDate
+)
+
+func keyPattern(s string) (res KeyPattern) {
+	if s == "" {
+		return 0
+	}
+	res = keyAllPatterns
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+		case r == '-':
+			if i > 0 || len(s) == 1 {
+				res &^= KeyInteger
+			}
+			res &^= KeyHex
+		case r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F':
+			res &^= KeyInteger
+		default:
+			res &^= KeyInteger | KeyHex
+		}
+	}
+	if res&KeyHex != 0 && len(s) < 8 {
+		res &^= KeyHex
+	}
+	if !isUUID(s) {
+		res &^= KeyUUID
+	}
+	if !isDateKey(s) {
+		res &^= KeyDate
+	}
+	return res
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := range len(s) {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isDateKey(s string) bool {
+	if len(s) < 10 {
+		return false
+	}
+	for i := range 10 {
+		c := s[i]
+		switch i {
+		case 4, 7:
+			if c != '-' {
+				return false
+			}
+		default:
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Best returns the most specific pattern from the set or 0 if the set is
+// empty.
+func (p KeyPattern) Best() KeyPattern {
+	for _, b := range []KeyPattern{KeyUUID, KeyDate, KeyInteger, KeyHex} {
+		if p&b != 0 {
+			return b
+		}
+	}
+	return 0
+}
+
+func (p KeyPattern) String() string {
+	switch p.Best() {
+	case KeyUUID:
+		return "uuid"
+	case KeyDate:
+		return "date"
+	case KeyInteger:
+		return "integer"
+	case KeyHex:
+		return "hex"
+	}
+	return "any"
+}
+
+// Regexp returns a regular expression for the most specific pattern in the
+// set. If the set is empty, the empty string is returned.
+func (p KeyPattern) Regexp() string {
+	switch p.Best() {
+	case KeyUUID:
+		return "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"
+	case KeyDate:
+		return "^[0-9]{4}-[0-9]{2}-[0-9]{2}"
+	case KeyInteger:
+		return "^-?[0-9]+$"
+	case KeyHex:
+		return "^[0-9a-fA-F]+$"
+	}
+	return ""
+}
+
+func newMap(cfg *Config, count, nulln int) *Map {
+	return &Map{
+		dedBase: dedBase{cfg: cfg, Count: count, Null: nulln},
+		MinLen:  -1,
+		MaxLen:  -1,
+		Value:   NewUnknown(cfg),
+	}
+}
+
+func newMapFromObj(o *Object) *Map {
+	res := newMap(o.cfg, o.Count, o.Null)
+	for n, m := range o.Members {
+		res.key(n, m.Occurence)
+		res.Value = merge(res.Value, m.Ded)
+	}
+	return res
+}
+
+func (*Map) JsonType() JsonType { return JsonObject }
+
+func (m *Map) Accepts(v any, jt JsumType) float64 {
+	if mbrs := objSeq(v, jt); mbrs != nil {
+		if m.Pattern == 0 {
+			return 1
+		}
+		p := m.Pattern
+		for k := range mbrs {
+			if p &= keyPattern(k); p == 0 {
+				return math.SmallestNonzeroFloat64
+			}
+		}
+		return 1
+	}
+	return 0
+}
+
+func (m *Map) Example(v any, jt JsumType, _ float64) Deducer {
+	if jt.t == JsonNull {
+		m.Count++
+		m.Null++
+		return m
+	}
+	if mbrs := objSeq(v, jt); mbrs != nil {
+		m.Count++
+		for k, e := range mbrs {
+			m.key(k, 1)
+			m.Value = m.Value.Example(e, JsonTypeOf(e), UnknownAccept)
+		}
+		return m
+	}
+	if jt.t == JsonObject {
+		return newAnyFrom(m).Example(v, jt, UnknownAccept)
+	}
+	u := newUnion(m)
+	return u.Example(v, jt, UnknownAccept)
+}
+
+func (m *Map) key(k string, n int) {
+	m.Entries += n
+	l := utf8.RuneCountInString(k)
+	if m.MinLen < 0 {
+		m.MinLen, m.MaxLen = l, l
+		m.Pattern = keyPattern(k)
+	} else {
+		m.MinLen = min(m.MinLen, l)
+		m.MaxLen = max(m.MaxLen, l)
+		m.Pattern &= keyPattern(k)
+	}
+}
+
+// MeanSize returns the mean number of entries per non-null map.
+func (m *Map) MeanSize() float64 {
+	if n := m.Count - m.Null; n > 0 {
+		return float64(m.Entries) / float64(n)
+	}
+	return 0
+}
+
+func (m *Map) mergeMap(b *Map) *Map {
+	m.Count += b.Count
+	m.Null += b.Null
+	m.Entries += b.Entries
+	switch {
+	case b.MinLen < 0:
+	case m.MinLen < 0:
+		m.MinLen, m.MaxLen = b.MinLen, b.MaxLen
+		m.Pattern = b.Pattern
+	default:
+		m.MinLen = min(m.MinLen, b.MinLen)
+		m.MaxLen = max(m.MaxLen, b.MaxLen)
+		m.Pattern &= b.Pattern
+	}
+	m.Value = merge(m.Value, b.Value)
+	return m
+}
+
+func (m *Map) Hash(dh DedupHash) uint64 {
+	hash := m.dedBase.startHash(JsonObject)
+	hash.WriteByte(2)
+	binary.Write(hash, hashEndian, uint32(m.Pattern.Best()))
+	vh := m.Value.Hash(dh)
+	binary.Write(hash, hashEndian, vh)
+	res := hash.Sum64()
+	dh.add(res, m)
+	return res
+}
+
+func (m *Map) Equal(d Deducer) bool {
+	b, ok := d.(*Map)
+	if !ok {
+		return false
+	}
+	if !m.dedBase.Equal(&b.dedBase) {
+		return false
+	}
+	if m.Pattern.Best() != b.Pattern.Best() {
+		return false
+	}
+	return m.Value.Equal(b.Value)
+}
+
+func (m *Map) JSONSchema(opts *SchemaOptions) any {
+	res := jscmMap{
+		jscmType:   schemaType("object", m.Null > 0),
+		Additional: schemaOf(m.Value, opts),
+	}
+	if p := m.Pattern.Regexp(); p != "" && opts.Strictness != SchemaLoose {
+		res.PropNames = &jscmPropNames{Pattern: p}
+	}
+	return res
+}
+
+func (m *Map) super() *dedBase { return &m.dedBase }
+
+// mapKeys reports whether the member names of o and the keys in k all match
+// a common key pattern. Such objects are merged even if they do not accept
+// each other so that map detection gets a chance.
+func (o *Object) mapKeys(k iter.Seq2[string, any]) bool {
+	if o.cfg.Map.MinMembers <= 0 {
+		return false
+	}
+	p := keyAllPatterns
+	for n := range o.Members {
+		if p &= keyPattern(n); p == 0 {
+			return false
+		}
+	}
+	for n := range k {
+		if p &= keyPattern(n); p == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mapShapeMin is the minimum share of common member names that object values
+// of a map must have with the first object value.
+const mapShapeMin = 0.5
+
+// isMap checks the Config.Map thresholds to decide if o is rather a map than
+// a record. An object with at least MinMembers distinct members is a map if
+// its members occur rarely or if all member values have the same shape. The
+// latter also detects maps in a single example, where each member occurs in
+// every object.
+func (o *Object) isMap() bool {
+	mc := &o.cfg.Map
+	if mc.MinMembers <= 0 || len(o.Members) < mc.MinMembers {
+		return false
+	}
+	n := o.Count - o.Null
+	if n <= 0 {
+		return false
+	}
+	occ := 0
+	for _, m := range o.Members {
+		occ += m.Occurence
+	}
+	mean := float64(occ) / float64(len(o.Members)) / float64(n)
+	return mean <= mc.MaxOccurence || o.sameValueShape()
+}
+
+// sameValueShape reports whether all member values have the same JSON type
+// and objects are similar. Records with many members of the same scalar type
+// are common, so scalar values also need member names with a common key
+// pattern.
+func (o *Object) sameValueShape() bool {
+	var first Deducer
+	p := keyAllPatterns
+	for n, m := range o.Members {
+		p &= keyPattern(n)
+		switch {
+		case m.Ded.JsonType() == JsonUnknown:
+		case first == nil:
+			first = m.Ded
+		case !sameShape(first, m.Ded):
+			return false
+		}
+	}
+	switch {
+	case first == nil:
+		return false
+	case first.JsonType().scalar():
+		return p != 0
+	}
+	return first.JsonType() != JsonUnion
+}
+
+// sameShape reports whether a and b have the same JSON type and, if both are
+// objects, share at least mapShapeMin of their member names.
+func sameShape(a, b Deducer) bool {
+	if a.JsonType() != b.JsonType() {
+		return false
+	}
+	ao, aok := a.(*Object)
+	bo, bok := b.(*Object)
+	if !aok || !bok {
+		return true
+	}
+	shared := 0
+	for n := range ao.Members {
+		if _, ok := bo.Members[n]; ok {
+			shared++
+		}
+	}
+	total := len(ao.Members) + len(bo.Members) - shared
+	return total == 0 || float64(shared)/float64(total) >= mapShapeMin
+}
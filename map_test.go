@@ -0,0 +1,142 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestKeyPattern(t *testing.T) {
+	for _, c := range []struct {
+		key  string
+		best KeyPattern
+	}{
+		{"", 0},
+		{"name", 0},
+		{"4711", KeyInteger},
+		{"-17", KeyInteger},
+		{"deadbeef42", KeyHex},
+		{"123e4567-e89b-12d3-a456-426614174000", KeyUUID},
+		{"2025-10-18", KeyDate},
+		{"2025-10-18T12:00:00Z", KeyDate},
+	} {
+		if b := keyPattern(c.key).Best(); b != c.best {
+			t.Errorf("key '%s' has pattern %s, expected %s", c.key, b, c.best)
+		}
+	}
+}
+
+func TestObject_toMap(t *testing.T) {
+	cfg := Config{
+		Union: UnionConfig{
+			MergeRejectMax: math.SmallestNonzeroFloat64,
+			Combine:        []TypeSet{AllTypes},
+		},
+		Map: MapConfig{MinMembers: 10, MaxOccurence: 0.2},
+	}
+	var d Deducer = NewUnknown(&cfg)
+	for i := range 20 {
+		v := map[string]any{fmt.Sprint(i): float64(i)}
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	m, ok := d.(*Map)
+	if !ok {
+		t.Fatalf("deduced not map but %T", d)
+	}
+	if m.Count != 20 || m.Entries != 20 {
+		t.Errorf("unexpected count %d / entries %d", m.Count, m.Entries)
+	}
+	if m.Pattern.Best() != KeyInteger {
+		t.Errorf("unexpected key pattern %s", m.Pattern)
+	}
+	if n, ok := m.Value.(*Number); !ok {
+		t.Errorf("map value not number but %T", m.Value)
+	} else if n.Count != 20 || n.Min != 0 || n.Max != 19 {
+		t.Errorf("unexpected map value %+v", n)
+	}
+}
+
+func TestObject_singleMap(t *testing.T) {
+	cfg := testCfg
+	cfg.Map = MapConfig{MinMembers: 32, MaxOccurence: 0.1}
+	users := make(map[string]any)
+	for i := range 1000 {
+		users[fmt.Sprint(1000+i)] = map[string]any{
+			"name": fmt.Sprint("user", i),
+			"age":  float64(i % 100),
+		}
+	}
+	if m, ok := Deduce(&cfg, users).(*Map); !ok {
+		t.Errorf("root map not detected")
+	} else if m.Entries != 1000 || m.Pattern.Best() != KeyInteger {
+		t.Errorf("unexpected root map %d entries, pattern %s", m.Entries, m.Pattern)
+	}
+	d := Deduce(&cfg, map[string]any{"users": users})
+	o, ok := d.(*Object)
+	if !ok {
+		t.Fatalf("deduced %T", d)
+	}
+	if m, ok := o.Members["users"].Ded.(*Map); !ok {
+		t.Errorf("nested map not detected")
+	} else if _, ok := m.Value.(*Object); !ok {
+		t.Errorf("map value not object but %T", m.Value)
+	}
+
+	named := make(map[string]any)
+	record := make(map[string]any)
+	for i := range 40 {
+		k := fmt.Sprintf("user_%c%c", 'a'+i/26, 'a'+i%26)
+		named[k] = map[string]any{"name": k, "age": float64(i)}
+		record[k] = k
+	}
+	if m, ok := Deduce(&cfg, named).(*Map); !ok {
+		t.Errorf("map without key pattern not detected")
+	} else if m.Pattern != 0 {
+		t.Errorf("unexpected key pattern %s", m.Pattern)
+	}
+	d = Deduce(&cfg, record)
+	if _, ok := d.(*Object); !ok {
+		t.Errorf("record of strings deduced as %T", d)
+	}
+}
+
+func TestMap_noKeys(t *testing.T) {
+	empty := func() *Map {
+		m := newMap(&testCfg, 0, 0)
+		v := map[string]any{}
+		return m.Example(v, JsonTypeOf(v), UnknownAccept).(*Map)
+	}
+	m := empty()
+	if m.Pattern != 0 {
+		t.Errorf("map without keys has pattern %s", m.Pattern)
+	}
+	if scm := m.JSONSchema(&SchemaOptions{}).(jscmMap); scm.PropNames != nil {
+		t.Errorf("map without keys has property names %+v", scm.PropNames)
+	}
+	v := map[string]any{"4711": 1.0}
+	keyed := newMap(&testCfg, 0, 0).Example(v, JsonTypeOf(v), UnknownAccept).(*Map)
+	if p := keyed.mergeMap(empty()).Pattern.Best(); p != KeyInteger {
+		t.Errorf("merged empty map changed pattern to %s", p)
+	}
+	if p := empty().mergeMap(keyed).Pattern.Best(); p != KeyInteger {
+		t.Errorf("merged into empty map got pattern %s", p)
+	}
+}
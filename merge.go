@@ -0,0 +1,376 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"maps"
+	"math"
+	"slices"
+)
+
+// merge combines what was learned by the deducers a and b into one deducer.
+// Both a and b may be modified and must not be used after merging. The
+// result is the merged deducer.
+func merge(a, b Deducer) Deducer {
+	switch a := a.(type) {
+	case *Unknown:
+		bb := b.super()
+		bb.Count += a.Count
+		bb.Null += a.Null
+		return b
+	case Invalid:
+		return a
+	case *Union:
+		return a.mergeDed(b)
+	case *Any:
+		a.Count += b.super().Count
+		a.Null += b.super().Null
+		a.addTypes(b)
+		return a
+	}
+	switch b := b.(type) {
+	case *Unknown:
+		ab := a.super()
+		ab.Count += b.Count
+		ab.Null += b.Null
+		return a
+	case Invalid:
+		return b
+	case *Union:
+		return newUnion(a).mergeDed(b)
+	case *Recursion:
+		return b.mergeRec(a)
+	case *Any:
+		b.Count += a.super().Count
+		b.Null += a.super().Null
+		b.addTypes(a)
+		return b
+	}
+	switch a := a.(type) {
+	case *Number:
+		if b, ok := b.(*Number); ok {
+			a.mergeNum(b)
+			return a
+		}
+	case *String:
+		if b, ok := b.(*String); ok {
+			a.mergeStr(b)
+			return a
+		}
+	case *Boolean:
+		if b, ok := b.(*Boolean); ok {
+			a.Count += b.Count
+			a.Null += b.Null
+			a.TrueNo += b.TrueNo
+			a.FalseNo += b.FalseNo
+			return a
+		}
+	case *Object:
+		switch b := b.(type) {
+		case *Object:
+			a.mergeObj(b)
+			return a
+		case *Map:
+			return b.mergeMap(newMapFromObj(a))
+		}
+	case *Map:
+		switch b := b.(type) {
+		case *Map:
+			return a.mergeMap(b)
+		case *Object:
+			return a.mergeMap(newMapFromObj(b))
+		}
+	case *Array:
+		if b, ok := b.(*Array); ok {
+			a.mergeArr(b)
+			return a
+		}
+	case *Tagged:
+		if b, ok := b.(*Tagged); ok && a.Tag == b.Tag {
+			return a.mergeTagged(b)
+		}
+	case *Grouped:
+		if b, ok := b.(*Grouped); ok && a.Path == b.Path {
+			return a.mergeGrouped(b)
+		}
+	case *Recursion:
+		return a.mergeRec(b)
+	}
+	return unionOf(a, b)
+}
+
+// unionOf returns the union of a and b that cannot be merged or Any if
+// UnionConfig.Combine does not allow the union. Unlike Union.mergeDed this
+// does not try to merge a and b again if they have the same JSON type.
+func unionOf(a, b Deducer) Deducer {
+	u := newUnion(a)
+	u.Count += b.super().Count
+	u.Null += b.super().Null
+	tset := NewTypeSet(a.JsonType(), b.JsonType())
+	for _, comb := range u.cfg.Union.Combine {
+		if comb&tset == tset {
+			u.Variants = append(u.Variants, b)
+			return u
+		}
+	}
+	res := newAnyFrom(u)
+	res.addTypes(b)
+	return res
+}
+
+// clone returns a deep copy of d that can be merged without changing d.
+// Recursions keep their Target.
+func clone(d Deducer) Deducer {
+	switch d := d.(type) {
+	case *Unknown:
+		c := *d
+		return &c
+	case *Any:
+		c := *d
+		c.Types = maps.Clone(d.Types)
+		return &c
+	case *String:
+		c := *d
+		c.Stats = maps.Clone(d.Stats)
+		return &c
+	case *Number:
+		c := *d
+		return &c
+	case *Boolean:
+		c := *d
+		return &c
+	case *Object:
+		c := *d
+		c.Members = make(map[string]Member, len(d.Members))
+		for n, m := range d.Members {
+			m.Ded = clone(m.Ded)
+			c.Members[n] = m
+		}
+		c.Shapes = maps.Clone(d.Shapes)
+		c.Orders = maps.Clone(d.Orders)
+		return &c
+	case *Map:
+		c := *d
+		c.Value = clone(d.Value)
+		return &c
+	case *Array:
+		c := *d
+		c.Elem = clone(d.Elem)
+		c.LenHist = slices.Clone(d.LenHist)
+		if d.Tuple != nil {
+			c.Tuple = make([]Deducer, len(d.Tuple))
+			for i, t := range d.Tuple {
+				c.Tuple[i] = clone(t)
+			}
+		}
+		return &c
+	case *Union:
+		c := *d
+		c.Variants = make([]Deducer, len(d.Variants))
+		for i, v := range d.Variants {
+			c.Variants[i] = clone(v)
+		}
+		return &c
+	case *Tagged:
+		c := *d
+		c.index = nil
+		c.Variants = make([]TagVariant, len(d.Variants))
+		for i, v := range d.Variants {
+			c.Variants[i] = TagVariant{Values: slices.Clone(v.Values), Ded: clone(v.Ded)}
+		}
+		return &c
+	case *Grouped:
+		c := *d
+		c.Groups = make(map[string]Deducer, len(d.Groups))
+		for k, g := range d.Groups {
+			c.Groups[k] = clone(g)
+		}
+		return &c
+	case *Recursion:
+		c := *d
+		return &c
+	}
+	return d
+}
+
+func (nr *Number) mergeNum(b *Number) {
+	nr.Count += b.Count
+	nr.Null += b.Null
+	nr.Min = min(nr.Min, b.Min)
+	nr.Max = max(nr.Max, b.Max)
+	nr.IsFloat = nr.IsFloat || b.IsFloat
+	nr.HasFrac = nr.HasFrac || b.HasFrac
+	if nr.Decimals < 0 || b.Decimals < 0 {
+		nr.Decimals = -1
+		return
+	}
+	d := max(nr.Decimals, b.Decimals)
+	ag, aok := scaleGCD(nr.GCD, d-nr.Decimals)
+	bg, bok := scaleGCD(b.GCD, d-b.Decimals)
+	if !aok || !bok {
+		nr.Decimals = -1
+		return
+	}
+	switch {
+	case nr.StepValues == 0:
+		nr.StepValues = b.StepValues
+	case b.StepValues == 0:
+	case nr.StepValues == 1 && b.StepValues == 1 && ag == bg:
+	default:
+		nr.StepValues = 2
+	}
+	nr.GCD, nr.Decimals = gcd(ag, bg), d
+}
+
+func scaleGCD(g uint64, decs int) (uint64, bool) {
+	if decs == 0 {
+		return g, true
+	}
+	f := uint64(math.Pow10(decs))
+	if g > (1<<53)/f {
+		return 0, false
+	}
+	return g * f, true
+}
+
+func (s *String) mergeStr(b *String) {
+	switch {
+	case len(b.Stats) == 0:
+	case len(s.Stats) == 0:
+		s.Format = b.Format
+	case s.Format != b.Format:
+		s.Format = 0
+	}
+	s.Count += b.Count
+	s.Null += b.Null
+	for str, n := range b.Stats {
+		s.Stats[str] += n
+	}
+}
+
+func (o *Object) mergeObj(b *Object) {
+	o.Count += b.Count
+	o.Null += b.Null
+	for n, bm := range b.Members {
+		if om, ok := o.Members[n]; ok {
+			ded := merge(om.Ded, bm.Ded)
+			// merging into a Recursion may have updated o's member
+			om = o.Members[n]
+			o.Members[n] = Member{
+				Occurence:  om.Occurence + bm.Occurence,
+				Duplicates: om.Duplicates + bm.Duplicates,
+				Nulls:      om.Nulls + bm.Nulls,
+				Empties:    om.Empties + bm.Empties,
+				Ded:        ded,
+			}
+		} else {
+			bm.seen = 0
+			o.Members[n] = bm
+		}
+	}
+	o.Duplicates += b.Duplicates
+	o.ShapeOverflow += b.ShapeOverflow
+	for sig, n := range b.Shapes {
+		o.addShape(sig, n)
+	}
+	o.OrderOverflow += b.OrderOverflow
+	for sig, n := range b.Orders {
+		o.addOrder(sig, n)
+	}
+}
+
+func (a *Array) mergeArr(b *Array) {
+	switch {
+	case b.MinLen < 0:
+	case a.MinLen < 0:
+		a.Tuple = b.Tuple
+	case len(a.Tuple) > 0 && len(a.Tuple) == len(b.Tuple):
+		for i, t := range a.Tuple {
+			if a.Tuple[i] = merge(t, b.Tuple[i]); !tupleStable(a.Tuple[i]) {
+				a.Tuple = nil
+				break
+			}
+		}
+	default:
+		a.Tuple = nil
+	}
+	switch {
+	case b.MinLen < 0:
+	case a.MinLen < 0:
+		a.MinLen, a.MaxLen = b.MinLen, b.MaxLen
+	default:
+		a.MinLen = min(a.MinLen, b.MinLen)
+		a.MaxLen = max(a.MaxLen, b.MaxLen)
+	}
+	a.Count += b.Count
+	a.Null += b.Null
+	a.Elems += b.Elems
+	a.Empty += b.Empty
+	a.Scalars += b.Scalars
+	a.UniqueNo += b.UniqueNo
+	a.AscNo += b.AscNo
+	a.DescNo += b.DescNo
+	for i, n := range b.LenHist {
+		if n > 0 {
+			a.addLenBucket(i, n)
+		}
+	}
+	a.Elem = merge(a.Elem, b.Elem)
+}
+
+// mergeDed merges d into the union. Variants of d are merged into variants of
+// the same JSON type. If this is not possible, the union becomes Any.
+func (u *Union) mergeDed(d Deducer) Deducer {
+	u.Count += d.super().Count
+	u.Null += d.super().Null
+	var vars []Deducer
+	if du, ok := d.(*Union); ok {
+		vars = du.Variants
+	} else {
+		vars = []Deducer{d}
+	}
+	for i, v := range vars {
+		if !u.addVariant(v) {
+			res := newAnyFrom(u)
+			for _, v := range vars[i:] {
+				res.addTypes(v)
+			}
+			return res
+		}
+	}
+	return u
+}
+
+func (u *Union) addVariant(d Deducer) bool {
+	tset := NewTypeSet(d.JsonType())
+	for i, v := range u.Variants {
+		if v.JsonType() == d.JsonType() {
+			u.Variants[i] = merge(v, d)
+			return true
+		}
+		tset.Add(v.JsonType())
+	}
+	for _, comb := range u.cfg.Union.Combine {
+		if comb&tset == tset {
+			u.Variants = append(u.Variants, d)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// NameStyle is a set of naming conventions a member name conforms to. A
+// single lower case word like "name" conforms to camelCase, snake_case and
+// kebab-case.
+type NameStyle uint
+
+const (
+	StyleCamel NameStyle = 1 << iota
+	StylePascal
+	StyleSnake
+	StyleKebab
+	StyleScreaming
+
+	styleAll = StyleCamel | StylePascal | StyleSnake | StyleKebab | StyleScreaming
+)
+
+func (s NameStyle) String() string {
+	var styles []string
+	for _, st := range []struct {
+		s NameStyle
+		n string
+	}{
+		{StyleCamel, "camelCase"},
+		{StylePascal, "PascalCase"},
+		{StyleSnake, "snake_case"},
+		{StyleKebab, "kebab-case"},
+		{StyleScreaming, "SCREAMING_CASE"},
+	} {
+		if s&st.s != 0 {
+			styles = append(styles, st.n)
+		}
+	}
+	if len(styles) == 0 {
+		return "unknown"
+	}
+	return strings.Join(styles, "|")
+}
+
+// NameStyleOf returns the set of naming conventions that name conforms to.
+// Names with characters other than ASCII letters, digits, '_' and '-' or
+// with mixed separators conform to none.
+func NameStyleOf(name string) NameStyle {
+	var lower, upper, under, dash bool
+	for i := range len(name) {
+		switch c := name[i]; {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+		case c == '_':
+			under = true
+		case c == '-':
+			dash = true
+		default:
+			return 0
+		}
+	}
+	first := byte(0)
+	if name != "" {
+		first = name[0]
+	}
+	switch {
+	case !lower && !upper, under && dash:
+		return 0
+	case under:
+		if !upper {
+			return StyleSnake
+		}
+		if !lower {
+			return StyleScreaming
+		}
+		return 0
+	case dash:
+		if !upper {
+			return StyleKebab
+		}
+		return 0
+	case !upper:
+		return StyleCamel | StyleSnake | StyleKebab
+	case !lower:
+		if len(name) == 1 {
+			return StylePascal | StyleScreaming
+		}
+		return StyleScreaming
+	case first >= 'a' && first <= 'z':
+		return StyleCamel
+	case first >= 'A' && first <= 'Z':
+		return StylePascal
+	}
+	return 0
+}
+
+// normalName returns name in lower case without separators to find names
+// that only differ by style or case.
+func normalName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "", "-", "").Replace(name)
+}
+
+// Naming is the result of the naming convention analysis of an object's
+// member names.
+type Naming struct {
+	// Styles counts the member names that conform to exactly one style.
+	Styles map[NameStyle]int
+	// Mixed is true if there is no style all member names conform to.
+	Mixed bool
+	// Similar are groups of member names that only differ by style or case.
+	Similar [][]string
+}
+
+// OK reports if no naming issues were found.
+func (n *Naming) OK() bool { return !n.Mixed && len(n.Similar) == 0 }
+
+// Naming analyses the naming conventions of the member names of o.
+func (o *Object) Naming() (res Naming) {
+	res.Styles = make(map[NameStyle]int)
+	common := styleAll
+	norms := make(map[string][]string)
+	for n := range o.Members {
+		if st := NameStyleOf(n); st != 0 {
+			common &= st
+			if st&(st-1) == 0 {
+				res.Styles[st]++
+			}
+		}
+		nn := normalName(n)
+		norms[nn] = append(norms[nn], n)
+	}
+	res.Mixed = common == 0
+	for _, ns := range norms {
+		if len(ns) > 1 {
+			slices.Sort(ns)
+			res.Similar = append(res.Similar, ns)
+		}
+	}
+	slices.SortFunc(res.Similar, slices.Compare)
+	return res
+}
+
+// NamingIssue is an object with naming issues at Path.
+type NamingIssue struct {
+	Path   string
+	Naming Naming
+}
+
+// NamingIssues walks the deducer tree d and returns the naming issues of all
+// objects in the order of their paths.
+func NamingIssues(d Deducer) (res []NamingIssue) {
+	add := func(o *Object, path string) {
+		if n := o.Naming(); !n.OK() {
+			res = append(res, NamingIssue{Path: path, Naming: n})
+		}
+	}
+	walkTypes(d, "$", "", func(d Deducer, path, _ string) {
+		switch d := d.(type) {
+		case *Object:
+			add(d, path)
+		case *Tagged:
+			for _, v := range d.Variants {
+				if o, ok := v.Ded.(*Object); ok {
+					add(o, path)
+				}
+			}
+		}
+	})
+	slices.SortStableFunc(res, func(a, b NamingIssue) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return res
+}
+
+// NamingLabels returns one line for mixed styles and one line for each group
+// of similar names.
+func NamingLabels(n *Naming) (res []string) {
+	if n.Mixed {
+		styles := slices.Sorted(maps.Keys(n.Styles))
+		strs := make([]string, len(styles))
+		for i, st := range styles {
+			strs[i] = fmt.Sprintf("%s %d", st, n.Styles[st])
+		}
+		res = append(res, "mixed naming: "+strings.Join(strs, ", "))
+	}
+	for _, s := range n.Similar {
+		res = append(res, "similar names: "+strings.Join(s, ", "))
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNameStyleOf(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		style NameStyle
+	}{
+		{"name", StyleCamel | StyleSnake | StyleKebab},
+		{"userId", StyleCamel},
+		{"UserId", StylePascal},
+		{"user_id", StyleSnake},
+		{"user-id", StyleKebab},
+		{"USER_ID", StyleScreaming},
+		{"ID", StyleScreaming},
+		{"X", StylePascal | StyleScreaming},
+		{"User_id", 0},
+		{"user_id-2", 0},
+		{"@type", 0},
+		{"42", 0},
+	} {
+		if s := NameStyleOf(c.name); s != c.style {
+			t.Errorf("'%s' has style %s, expected %s", c.name, s, c.style)
+		}
+	}
+}
+
+func TestObject_Naming(t *testing.T) {
+	o := newObjJson(&testCfg, 0, 0, map[string]any{
+		"id":      1.0,
+		"userId":  2.0,
+		"user_id": 3.0,
+		"name":    "foo",
+	}, JsumType{t: JsonObject, v: jsonObjStrAny}).(*Object)
+	n := o.Naming()
+	if !n.Mixed {
+		t.Error("mixed naming not detected")
+	}
+	if !slices.EqualFunc(n.Similar, [][]string{{"userId", "user_id"}}, slices.Equal) {
+		t.Errorf("unexpected similar names %v", n.Similar)
+	}
+	delete(o.Members, "user_id")
+	if n = o.Naming(); !n.OK() {
+		t.Errorf("unexpected naming issues %+v", n)
+	}
+}
+
+func TestNamingIssues_tuple(t *testing.T) {
+	cfg := Config{Array: ArrayConfig{MaxTuple: 2}}
+	bad := map[string]any{"userId": 1.0, "user_id": 2.0}
+	d := deduceAll(&cfg, []any{1.0, bad}, []any{2.0, bad})
+	issues := NamingIssues(d)
+	if !slices.ContainsFunc(issues, func(i NamingIssue) bool { return i.Path == "$[1]" }) {
+		t.Errorf("no naming issue in tuple: %+v", issues)
+	}
+}
@@ -21,6 +21,8 @@ package jsum
 import (
 	"encoding/binary"
 	"math"
+	"strconv"
+	"strings"
 )
 
 type Number struct {
@@ -29,8 +31,20 @@ type Number struct {
 	Max     float64 `json:"max"`
 	IsFloat bool    `json:"is-float"`
 	HasFrac bool    `json:"has-frac"`
+	// GCD is the greatest common divisor of all values scaled by
+	// 10^Decimals. Together with Decimals it gives the Step of the values.
+	GCD uint64 `json:"gcd"`
+	// Decimals is the maximum number of decimal places seen. It is negative
+	// if no step can be detected, e.g. because of too many decimal places.
+	Decimals int `json:"decimals"`
+	// StepValues is the number of distinct non-zero absolute values, counted
+	// up to 2. A single value is no evidence for a step.
+	StepValues int `json:"step-values,omitempty"`
 }
 
+// maxStepDecimals limits the decimal places considered for step detection.
+const maxStepDecimals = 9
+
 func newNum(cfg *Config, count, nulln int) *Number {
 	res := &Number{dedBase: dedBase{cfg: cfg, Count: count, Null: nulln},
 		Min: math.Inf(1),
@@ -61,6 +75,7 @@ func (nr *Number) Example(v any, jt JsumType, _ float64) Deducer {
 		nr.Max = max(nr.Max, x)
 		nr.IsFloat = nr.IsFloat || isFloat
 		nr.HasFrac = nr.HasFrac || frac != 0
+		nr.step(x)
 	default:
 		u := newUnion(nr)
 		return u.Example(v, jt, UnknownAccept)
@@ -68,6 +83,76 @@ func (nr *Number) Example(v any, jt JsumType, _ float64) Deducer {
 	return nr
 }
 
+func (nr *Number) step(x float64) {
+	if nr.Decimals < 0 {
+		return
+	}
+	if math.IsInf(x, 0) || math.IsNaN(x) {
+		nr.Decimals = -1
+		return
+	}
+	d := decimalPlaces(x)
+	if d > maxStepDecimals {
+		nr.Decimals = -1
+		return
+	}
+	if d > nr.Decimals {
+		f := uint64(math.Pow10(d - nr.Decimals))
+		if nr.GCD > (1<<53)/f {
+			nr.Decimals = -1
+			return
+		}
+		nr.GCD *= f
+		nr.Decimals = d
+	}
+	s := math.Round(math.Abs(x) * math.Pow10(nr.Decimals))
+	if s > 1<<53 {
+		nr.Decimals = -1
+		return
+	}
+	// With one distinct value the GCD is that value
+	switch {
+	case s == 0:
+	case nr.StepValues == 0:
+		nr.StepValues = 1
+	case nr.StepValues == 1 && uint64(s) != nr.GCD:
+		nr.StepValues = 2
+	}
+	nr.GCD = gcd(nr.GCD, uint64(s))
+}
+
+// Step returns the largest step that all values are a multiple of. If no step
+// could be detected ok is false.
+func (nr *Number) Step() (step float64, ok bool) {
+	if nr.Decimals < 0 || nr.GCD == 0 {
+		return 0, false
+	}
+	return float64(nr.GCD) / math.Pow10(nr.Decimals), true
+}
+
+// stepRelevant reports whether the step is worth to be mentioned, i.e. it is
+// not simply 1 for integer values and there were at least two distinct
+// non-zero values.
+func (nr *Number) stepRelevant() bool {
+	_, ok := nr.Step()
+	return ok && nr.StepValues > 1 && (nr.Decimals > 0 || nr.GCD > 1)
+}
+
+func decimalPlaces(x float64) int {
+	s := strconv.FormatFloat(x, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
 func (nr *Number) Hash(dh DedupHash) uint64 {
 	hash := nr.dedBase.startHash(JsonNumber)
 	if nr.cfg.Dedup.Number&DedpuNumberIntFloat != 0 {
@@ -105,7 +190,7 @@ func (nr *Number) Hash(dh DedupHash) uint64 {
 		}
 	}
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], nr)
+	dh.add(res, nr)
 	return res
 }
 
@@ -136,20 +221,21 @@ func (nr *Number) Equal(d Deducer) bool {
 	return res
 }
 
-func (nr *Number) JSONSchema() any {
-	scm := jscmNumber{
-		Min: new(float64),
-		Max: new(float64),
-	}
+func (nr *Number) JSONSchema(opts *SchemaOptions) any {
+	var scm jscmNumber
 	if nr.IsFloat && nr.HasFrac {
-		scm.Type = "number"
+		scm.jscmType = schemaType("number", nr.Null > 0)
 	} else {
-		scm.Type = "integer"
+		scm.jscmType = schemaType("integer", nr.Null > 0)
+	}
+	if opts.Strictness == SchemaLoose {
+		return scm
 	}
-	*scm.Min = nr.Min
-	*scm.Max = nr.Max
-	if nr.Null > 0 {
-		return []any{"null", scm}
+	mi, ma := nr.Min, nr.Max
+	scm.Min, scm.Max = &mi, &ma
+	if nr.stepRelevant() {
+		step, _ := nr.Step()
+		scm.MultipleOf = &step
 	}
 	return scm
 }
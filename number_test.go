@@ -0,0 +1,74 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import "testing"
+
+func TestNumber_Step(t *testing.T) {
+	test := func(t *testing.T, step float64, xs ...any) {
+		t.Helper()
+		var d Deducer = NewUnknown(&testCfg)
+		for _, x := range xs {
+			d = d.Example(x, JsonTypeOf(x), UnknownAccept)
+		}
+		nr, ok := d.(*Number)
+		if !ok {
+			t.Fatalf("deduced not number but %T", d)
+		}
+		s, ok := nr.Step()
+		if step == 0 {
+			if ok {
+				t.Errorf("unexpected step %g", s)
+			}
+		} else if !ok {
+			t.Errorf("no step detected, expected %g", step)
+		} else if s != step {
+			t.Errorf("step %g not %g", s, step)
+		}
+	}
+	t.Run("ints", func(t *testing.T) { test(t, 1, 3, 7, -4) })
+	t.Run("1024", func(t *testing.T) { test(t, 1024, 1024, 0, 4096, 3072) })
+	t.Run("prices", func(t *testing.T) { test(t, 0.01, 1.99, 2.5, 10.0) })
+	t.Run("quarters", func(t *testing.T) { test(t, 0.25, 0.25, 1.5, 2.75) })
+	t.Run("too fine", func(t *testing.T) { test(t, 0, 1.0, 3.1415926535) })
+}
+
+func TestNumber_stepRelevant(t *testing.T) {
+	deduce := func(xs ...any) *Number { return deduceAll(&testCfg, xs...).(*Number) }
+	for _, test := range []struct {
+		xs  []any
+		rel bool
+	}{
+		{[]any{2.0, 2.0, 2.0}, false},
+		{[]any{0.5}, false},
+		{[]any{0.0, -2.0, 2.0}, false},
+		{[]any{2.0, 4.0}, true},
+		{[]any{0.5, 1.5}, true},
+	} {
+		if r := deduce(test.xs...).stepRelevant(); r != test.rel {
+			t.Errorf("%v: step relevant is %t", test.xs, r)
+		}
+	}
+	if nr := merge(deduce(2.0), deduce(2.0)).(*Number); nr.stepRelevant() {
+		t.Error("merged constants have relevant step")
+	}
+	if nr := merge(deduce(2.0), deduce(4.0)).(*Number); !nr.stepRelevant() {
+		t.Error("merged distinct values have no relevant step")
+	}
+}
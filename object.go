@@ -21,39 +21,97 @@ package jsum
 import (
 	"encoding/binary"
 	"iter"
+	"maps"
 	"math"
+	"reflect"
 	"slices"
-	"sort"
+	"strings"
 )
 
 type Object struct {
 	dedBase
 	Members map[string]Member `json:"members"`
+	// Shapes counts the observed sets of members, see Shape. At most
+	// ObjectConfig.MaxShapes are tracked, further shapes are only counted in
+	// ShapeOverflow.
+	Shapes        map[string]int `json:"shapes,omitempty"`
+	ShapeOverflow int            `json:"shape-overflow,omitempty"`
+	// Orders counts the observed member orders of objects that were read as
+	// OrderedObject. At most ObjectConfig.MaxOrders are tracked, further
+	// orders are only counted in OrderOverflow.
+	Orders        map[string]int `json:"orders,omitempty"`
+	OrderOverflow int            `json:"order-overflow,omitempty"`
+	// Duplicates is the number of objects with duplicate member names.
+	Duplicates int `json:"duplicates,omitempty"`
 }
 
 type Member struct {
-	Occurence int     `json:"occurence"`
-	Ded       Deducer `json:"type"`
+	Occurence int `json:"occurence"`
+	// Duplicates is the number of objects that had this member more than
+	// once. All values of duplicate members are examples for Ded.
+	Duplicates int `json:"duplicates,omitempty"`
+	// Nulls and Empties are the number of objects that had this member with
+	// the value null or with an empty string, array or object. Only the first
+	// occurence of duplicate members is counted.
+	Nulls   int     `json:"nulls,omitempty"`
+	Empties int     `json:"empties,omitempty"`
+	Ded     Deducer `json:"type"`
+	// seen is o.Count of the last object that had the member, negated if the
+	// member was a duplicate in that object.
+	seen int
 }
 
-func newObjJson(cfg *Config, count, nulln int, m iter.Seq2[string, any]) *Object {
+// Values returns the number of objects that had the member with a non-null,
+// non-empty value.
+func (m *Member) Values() int { return m.Occurence - m.Nulls - m.Empties }
+
+func (m *Member) addValue(v any) {
+	switch jt := JsonTypeOf(v); {
+	case jt.t == JsonNull:
+		m.Nulls++
+	case emptyValue(v, jt):
+		m.Empties++
+	}
+}
+
+func emptyValue(v any, jt JsumType) bool {
+	switch jt.t {
+	case JsonString:
+		s, ok := v.(string)
+		return ok && s == ""
+	case JsonArray, JsonObject:
+		if o, ok := v.(OrderedObject); ok {
+			return len(o) == 0
+		}
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return rv.Len() == 0
+		}
+	}
+	return false
+}
+
+func newObjJson(cfg *Config, count, nulln int, v any, jt JsumType) Deducer {
 	res := &Object{
 		dedBase: dedBase{cfg: cfg, Count: count, Null: nulln},
 		Members: make(map[string]Member),
 	}
-	if m != nil {
+	if m := objSeq(v, jt); m != nil {
 		res.Count++
+		res.addExample(m, jt.v == jsonObjOrdered)
+		if res.isMap() {
+			return newMapFromObj(res)
+		}
 	}
-	res.mergeMap(m)
 	return res
 }
 
 func (*Object) JsonType() JsonType { return JsonObject }
 
 func (o *Object) Accepts(v any, jt JsumType) float64 {
-	switch jt.v {
-	case jsonObjStrAny:
-		acpt := o.acceptance(objStrAnySeq(v))
+	if m := objSeq(v, jt); m != nil {
+		acpt := o.acceptance(m)
 		return max(math.SmallestNonzeroFloat64, acpt)
 	}
 	return 0
@@ -65,70 +123,137 @@ func (o *Object) Example(v any, jt JsumType, acpt float64) Deducer {
 		o.Null++
 		return o
 	}
-	switch jt.v {
-	case jsonObjStrAny:
+	if m := objSeq(v, jt); m != nil {
 		if acpt < 0 {
-			acpt = o.acceptance(objStrAnySeq(v))
+			acpt = o.acceptance(m)
 		}
-		if o.cfg.Union.MergeRejectMax == 0 || acpt > o.cfg.Union.MergeRejectMax {
+		if o.cfg.Union.MergeRejectMax == 0 ||
+			acpt > o.cfg.Union.MergeRejectMax ||
+			o.mapKeys(m) {
 			o.Count++
-			o.mergeMap(objStrAnySeq(v))
+			o.addExample(m, jt.v == jsonObjOrdered)
+			if o.isMap() {
+				return newMapFromObj(o)
+			}
 			return o
 		}
 		u := newUnion(o)
 		return u.Example(v, jt, UnknownAccept)
-		// TODO case jsonObjRMap:
 	}
-	return newAny(o.cfg, o.Count+1, o.Null) // TODO Why not union?
+	if jt.t == JsonObject {
+		return newAnyFrom(o).Example(v, jt, UnknownAccept)
+	}
+	return newUnion(o).Example(v, jt, UnknownAccept)
 }
 
 func (o *Object) acceptance(m iter.Seq2[string, any]) float64 {
-	common, vmiss := 0, 0
+	ucfg := &o.cfg.Union
+	var common, total, vmiss float64
 	for n, v := range m {
-		vjt := JsonTypeOf(v)
-		if m, ok := o.Members[n]; ok && m.Ded.JsonType() == vjt.JsonType() {
-			common++
+		if m, ok := o.Members[n]; ok &&
+			(ucfg.AcceptAnyType || m.Ded.JsonType() == JsonTypeOf(v).JsonType()) {
+			common += o.memberWeight(ucfg.Accept, m)
 		} else {
 			vmiss++
 		}
 	}
-	total := len(o.Members) + vmiss
+	switch ucfg.Accept {
+	case AcceptStrict:
+		if vmiss > 0 || common < float64(len(o.Members)) {
+			return 0
+		}
+		return 1
+	case AcceptWeighted:
+		for _, m := range o.Members {
+			total += o.memberWeight(ucfg.Accept, m)
+		}
+	default:
+		total = float64(len(o.Members))
+	}
+	total += vmiss
 	if total == 0 {
 		return 1
 	}
-	return float64(common) / float64(total)
+	return common / total
+}
+
+func (o *Object) memberWeight(acpt Acceptance, m Member) float64 {
+	if acpt == AcceptWeighted && o.Count > 0 {
+		return float64(m.Occurence) / float64(o.Count)
+	}
+	return 1
 }
 
-func (o *Object) mergeMap(m iter.Seq2[string, any]) {
+// addExample merges the members of one example object that was already
+// counted in o.Count and tracks its shape and, if ordered, its member order.
+func (o *Object) addExample(m iter.Seq2[string, any], ordered bool) {
+	names, dup := o.mergeMap(m)
+	if dup {
+		o.Duplicates++
+	}
+	if ordered {
+		o.addOrder(strings.Join(names, shapeSep), 1)
+	}
+	slices.Sort(names)
+	o.addShape(strings.Join(names, shapeSep), 1)
+}
+
+// mergeMap merges the members of one example object into o. It returns the
+// member names in the order of their first occurence and whether there were
+// duplicate member names.
+func (o *Object) mergeMap(m iter.Seq2[string, any]) (names []string, dup bool) {
 	for k, v := range m {
 		if m, ok := o.Members[k]; ok {
-			o.Members[k] = Member{
-				Occurence: m.Occurence + 1,
-				Ded:       m.Ded.Example(v, JsonTypeOf(v), UnknownAccept),
+			switch m.seen {
+			case o.Count: // 2nd occurence in this object
+				m.Duplicates++
+				m.seen = -o.Count
+				dup = true
+			case -o.Count: // already counted as duplicate
+			default:
+				m.Occurence++
+				m.addValue(v)
+				m.seen = o.Count
+				names = append(names, k)
 			}
+			m.Ded = m.Ded.Example(v, JsonTypeOf(v), UnknownAccept)
+			o.Members[k] = m
 		} else {
-			o.Members[k] = Member{Occurence: 1, Ded: Deduce(o.cfg, v)}
+			m := Member{Occurence: 1, Ded: Deduce(o.cfg, v), seen: o.Count}
+			m.addValue(v)
+			o.Members[k] = m
+			names = append(names, k)
 		}
 	}
+	return names, dup
 }
 
+// Objects returns the number of non-null objects, i.e. the number of objects
+// that could have members.
+func (o *Object) Objects() int { return o.Count - o.Null }
+
+// Absent returns the number of non-null objects that did not have member m.
+func (o *Object) Absent(m *Member) int { return o.Objects() - m.Occurence }
+
 func (o *Object) Hash(dh DedupHash) uint64 {
-	type memhash struct {
-		n string
-		h uint64
-	}
-	mems := make([]memhash, 0, len(o.Members))
-	for n, m := range o.Members {
-		mh := m.Ded.Hash(dh)
-		mems = append(mems, memhash{n, mh})
+	names := slices.Sorted(maps.Keys(o.Members))
+	mhs := make([]uint64, len(names))
+	for i, n := range names {
+		mhs[i] = o.Members[n].Ded.Hash(dh)
 	}
-	sort.Slice(mems, func(i, j int) bool { return mems[i].n < mems[j].n })
 	hash := o.dedBase.startHash(JsonObject)
-	for _, m := range mems {
-		binary.Write(hash, hashEndian, m.h)
+	for i, n := range names {
+		m := o.Members[n]
+		hash.WriteString(n)
+		if o.Absent(&m) > 0 {
+			hash.WriteByte(0)
+		} else {
+			hash.WriteByte(1)
+		}
+		binary.Write(hash, hashEndian, mhs[i])
 	}
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], o)
+	dh.add(res, o)
 	return res
 }
 
@@ -137,32 +262,39 @@ func (o *Object) Equal(d Deducer) bool {
 	if !ok {
 		return false
 	}
-	res := o.dedBase.Equal(&b.dedBase) && len(o.Members) == len(b.Members)
-	if res {
-		for i := range o.Members {
-			if res = o.Members[i].Ded.Equal(b.Members[i].Ded); !res {
-				break
-			}
+	if !o.dedBase.Equal(&b.dedBase) || len(o.Members) != len(b.Members) {
+		return false
+	}
+	for n, om := range o.Members {
+		bm, ok := b.Members[n]
+		if !ok || (o.Absent(&om) > 0) != (b.Absent(&bm) > 0) {
+			return false
+		}
+		if !om.Ded.Equal(bm.Ded) {
+			return false
 		}
 	}
-	return res
+	return true
 }
 
-func (o *Object) JSONSchema() any {
+func (o *Object) JSONSchema(opts *SchemaOptions) any {
 	res := jscmObj{
-		jscmType: jscmType{Type: "object"},
+		jscmType: schemaType("object", o.Null > 0),
 		Props:    make(map[string]any, len(o.Members)),
 	}
 	for n, t := range o.Members {
-		ded := t.Ded
-		res.Props[n] = ded.JSONSchema()
-		if t.Occurence == o.Count {
+		ps := schemaOf(t.Ded, opts)
+		if opts.Annotate {
+			ps = annotateMember(o, &t, ps)
+		}
+		res.Props[n] = ps
+		if o.Absent(&t) == 0 {
 			res.Required = append(res.Required, n)
 		}
 	}
 	slices.Sort(res.Required)
-	if o.Null > 0 {
-		return []any{"null", res}
+	if opts.Strictness == SchemaStrict {
+		res.Additional = false
 	}
 	return res
 }
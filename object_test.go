@@ -0,0 +1,144 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestObject_acceptance(t *testing.T) {
+	obj := func(cfg *Config) *Object {
+		var d Deducer = NewUnknown(cfg)
+		for _, v := range []any{
+			map[string]any{"a": 1.0, "b": "x"},
+			map[string]any{"a": 1.0},
+		} {
+			d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+		}
+		return d.(*Object)
+	}
+	tests := []struct {
+		accept  Acceptance
+		anyType bool
+		v       map[string]any
+		want    float64
+	}{
+		{AcceptJaccard, false, map[string]any{"a": 1.0}, 0.5},
+		{AcceptJaccard, false, map[string]any{"a": 1.0, "b": 2.0}, 1.0 / 3},
+		{AcceptJaccard, true, map[string]any{"a": 1.0, "b": 2.0}, 1},
+		{AcceptWeighted, false, map[string]any{"a": 1.0}, 2.0 / 3},
+		{AcceptWeighted, false, map[string]any{"a": 1.0, "c": 2.0}, 0.4},
+		{AcceptStrict, false, map[string]any{"a": 1.0}, 0},
+		{AcceptStrict, false, map[string]any{"a": 1.0, "b": 2.0}, 0},
+		{AcceptStrict, true, map[string]any{"a": 1.0, "b": 2.0}, 1},
+		{AcceptStrict, false, map[string]any{"b": "y", "a": 2.0}, 1},
+	}
+	for _, test := range tests {
+		cfg := Config{Union: UnionConfig{Accept: test.accept, AcceptAnyType: test.anyType}}
+		o := obj(&cfg)
+		if a := o.acceptance(objStrAnySeq(test.v)); math.Abs(a-test.want) > 1e-9 {
+			t.Errorf("%s any-type:%t %v: acceptance %f, want %f",
+				test.accept, test.anyType, test.v, a, test.want)
+		}
+	}
+}
+
+func TestAcceptance_text(t *testing.T) {
+	for _, a := range []Acceptance{AcceptJaccard, AcceptWeighted, AcceptStrict} {
+		txt, err := a.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b Acceptance
+		if err := b.UnmarshalText(txt); err != nil {
+			t.Fatal(err)
+		}
+		if b != a {
+			t.Errorf("%s read as %s", a, b)
+		}
+	}
+	var a Acceptance
+	if err := a.UnmarshalText([]byte("fuzzy")); err == nil {
+		t.Error("no error for unknown acceptance")
+	}
+}
+
+func TestObject_memberValues(t *testing.T) {
+	var d Deducer = NewUnknown(&testCfg)
+	for _, v := range []any{
+		map[string]any{"a": "x", "b": []any{1.0}},
+		map[string]any{"a": nil, "b": []any{}},
+		map[string]any{"a": "", "b": []any{}},
+		map[string]any{"b": []any{}},
+		nil,
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	o := d.(*Object)
+	a := o.Members["a"]
+	if a.Values() != 1 || a.Nulls != 1 || a.Empties != 1 || o.Absent(&a) != 1 {
+		t.Errorf("unexpected a: value:%d null:%d empty:%d absent:%d",
+			a.Values(), a.Nulls, a.Empties, o.Absent(&a))
+	}
+	if s, ok := a.Ded.(*String); !ok || s.Count != 3 || s.Null != 1 {
+		t.Errorf("unexpected type of a: %s", a.Ded.JsonType())
+	}
+	if l := MemberValuesLabel(o, &a); l != "[value:1 null:1 empty:1 absent:1]" {
+		t.Errorf("unexpected label '%s'", l)
+	}
+	b := o.Members["b"]
+	if b.Values() != 1 || b.Nulls != 0 || b.Empties != 3 || o.Absent(&b) != 0 {
+		t.Errorf("unexpected b: value:%d null:%d empty:%d absent:%d",
+			b.Values(), b.Nulls, b.Empties, o.Absent(&b))
+	}
+	scm := o.JSONSchema(&SchemaOptions{}).(jscmObj)
+	if !slices.Equal(scm.Required, []string{"b"}) {
+		t.Errorf("unexpected required members %v", scm.Required)
+	}
+}
+
+func TestObject_reflectMap(t *testing.T) {
+	d := deduceAll(&testCfg, map[string]any{"a": "x"}, map[string]string{"a": "y"})
+	o, ok := d.(*Object)
+	if !ok {
+		t.Fatalf("deduced %T", d)
+	}
+	if m := o.Members["a"]; o.Count != 2 || m.Occurence != 2 {
+		t.Errorf("unexpected object %+v", o)
+	}
+	cfg := Config{Union: UnionConfig{Combine: []TypeSet{AllTypes}}}
+	d = deduceAll(&cfg, "s", map[string]int{"n": 1}, map[int]string{1: "x"})
+	u, ok := d.(*Union)
+	if !ok {
+		t.Fatalf("deduced %T", d)
+	}
+	for _, v := range u.Variants {
+		if _, ok := v.(Invalid); ok {
+			t.Fatal("invalid union variant")
+		}
+	}
+	if o, ok := u.Variants[1].(*Object); !ok || o.Count != 1 {
+		t.Errorf("unexpected object variant %T", u.Variants[1])
+	}
+	if a, ok := u.Variants[2].(*Any); !ok || a.Types[JsonObject] != 1 {
+		t.Errorf("unexpected variant for unsupported map %T", u.Variants[2])
+	}
+}
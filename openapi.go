@@ -0,0 +1,142 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIVersion is the version of the documents that OpenAPI generates.
+const OpenAPIVersion = "3.1.0"
+
+const openAPIRefs = "#/components/schemas/"
+
+// OpenAPI returns an OpenAPI document with the JSON Schema of d in
+// components/schemas. The root schema is named after SchemaOptions.Title or
+// "Root", reused types keep their names from NameTypes. Each variant of a
+// Tagged union becomes a component of its own that is referenced from the
+// discriminator mapping. OpenAPI 3.1 uses JSON Schema draft 2020-12, i.e.
+// nullable types are type arrays or anyOf with null instead of "nullable".
+// SchemaOptions.Draft and ID are ignored.
+func OpenAPI(d Deducer, opts SchemaOptions) (map[string]any, error) {
+	title := opts.Title
+	opts.Draft, opts.ID, opts.Title = Draft2020_12, "", ""
+	scm, err := Schema(d, opts)
+	if err != nil {
+		return nil, err
+	}
+	comps, _ := scm["$defs"].(map[string]any)
+	if comps == nil {
+		comps = make(map[string]any)
+	}
+	delete(scm, "$defs")
+	delete(scm, "$schema")
+	var root string
+	if ref, ok := scm["$ref"].(string); ok && len(scm) == 1 {
+		root = strings.TrimPrefix(ref, "#/$defs/")
+	} else {
+		root = "Root"
+		if title != "" {
+			root = typeName(title)
+		}
+		root = uniqueName(comps, root)
+		comps[root] = scm
+	}
+	var f func(scm map[string]any)
+	f = func(scm map[string]any) {
+		switch ref, _ := scm["$ref"].(string); {
+		case ref == "#":
+			scm["$ref"] = openAPIRefs + root
+		case strings.HasPrefix(ref, "#/$defs/"):
+			scm["$ref"] = openAPIRefs + strings.TrimPrefix(ref, "#/$defs/")
+		}
+		disc, ok := scm["discriminator"].(map[string]any)
+		if !ok {
+			return
+		}
+		tag, _ := disc["propertyName"].(string)
+		vars, _ := scm["oneOf"].([]any)
+		mapping := make(map[string]any)
+		for i, v := range vars {
+			vs, ok := v.(map[string]any)
+			if !ok || vs["$ref"] != nil {
+				continue
+			}
+			vals := tagValues(vs, tag)
+			if len(vals) == 0 {
+				continue
+			}
+			name := uniqueName(comps, typeName(vals[0]))
+			comps[name] = vs
+			walkSchema(vs, f)
+			vars[i] = map[string]any{"$ref": openAPIRefs + name}
+			for _, val := range vals {
+				mapping[val] = openAPIRefs + name
+			}
+		}
+		if len(mapping) > 0 {
+			disc["mapping"] = mapping
+		}
+	}
+	for _, n := range slices.Sorted(maps.Keys(comps)) {
+		walkSchema(comps[n], f)
+	}
+	if title == "" {
+		title = root
+	}
+	return map[string]any{
+		"openapi": OpenAPIVersion,
+		"info": map[string]any{
+			"title":   title,
+			"version": "1.0.0",
+		},
+		"components": map[string]any{"schemas": comps},
+	}, nil
+}
+
+// tagValues returns the values of the tag member of the generic JSON schema
+// of a Tagged variant.
+func tagValues(scm map[string]any, tag string) (res []string) {
+	props, _ := scm["properties"].(map[string]any)
+	ts, _ := props[tag].(map[string]any)
+	if c, ok := ts["const"].(string); ok {
+		return []string{c}
+	}
+	enum, _ := ts["enum"].([]any)
+	for _, e := range enum {
+		if s, ok := e.(string); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func uniqueName(used map[string]any, name string) string {
+	if _, ok := used[name]; !ok {
+		return name
+	}
+	for i := 2; ; i++ {
+		if n := name + strconv.Itoa(i); used[n] == nil {
+			return n
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenAPI(t *testing.T) {
+	cfg := Config{Union: UnionConfig{Tags: []string{"kind"}}}
+	addr := map[string]any{"street": "s", "zip": "1"}
+	d := deduceAll(&cfg,
+		map[string]any{"kind": "click", "x": 1.0, "from": addr, "to": addr},
+		map[string]any{"kind": "view", "page": nil, "from": addr, "to": addr},
+	)
+	doc, err := OpenAPI(d, SchemaOptions{Title: "event log"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(doc)
+	var js struct {
+		OpenAPI    string `json:"openapi"`
+		Info       struct{ Title, Version string }
+		Components struct {
+			Schemas map[string]map[string]any
+		}
+	}
+	if err = json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	if js.OpenAPI != OpenAPIVersion || js.Info.Title != "event log" || js.Info.Version == "" {
+		t.Errorf("unexpected header %+v", js)
+	}
+	scms := js.Components.Schemas
+	for _, n := range []string{"EventLog", "Click", "View", "To"} {
+		if scms[n] == nil {
+			t.Errorf("no component %s in %s", n, data)
+		}
+	}
+	root := scms["EventLog"]
+	disc, _ := json.Marshal(root["discriminator"])
+	if string(disc) != `{"mapping":{"click":"#/components/schemas/Click",`+
+		`"view":"#/components/schemas/View"},"propertyName":"kind"}` {
+		t.Errorf("unexpected discriminator %s", disc)
+	}
+	from, _ := json.Marshal(scms["Click"]["properties"].(map[string]any)["from"])
+	if string(from) != `{"$ref":"#/components/schemas/To"}` {
+		t.Errorf("unexpected reference %s", from)
+	}
+	page, _ := json.Marshal(scms["View"]["properties"].(map[string]any)["page"])
+	if string(page) != `{"type":"null"}` {
+		t.Errorf("unexpected null member %s", page)
+	}
+}
+
+func TestOpenAPI_recursion(t *testing.T) {
+	leaf := map[string]any{"name": "b", "children": []any{}}
+	v := map[string]any{"name": "a", "children": []any{leaf, leaf}}
+	d := FoldRecursion(Deduce(&testCfg, v))
+	doc, err := OpenAPI(d, SchemaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scms := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	if len(scms) != 1 || scms["Root"] == nil {
+		t.Fatalf("unexpected components %v", scms)
+	}
+	data, _ := json.Marshal(scms["Root"])
+	var root struct {
+		Properties struct {
+			Children struct{ Items map[string]string }
+		}
+	}
+	json.Unmarshal(data, &root)
+	if ref := root.Properties.Children.Items["$ref"]; ref != "#/components/schemas/Root" {
+		t.Errorf("unexpected recursive reference '%s' in %s", ref, data)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Recursion stands for a nested object that has the same members as an
+// enclosing object, see FoldRecursion. The examples of the nested object are
+// merged into the enclosing Target object.
+type Recursion struct {
+	dedBase
+	// Path is the path of Target.
+	Path   string  `json:"path"`
+	Target *Object `json:"-"`
+}
+
+func (*Recursion) JsonType() JsonType { return JsonObject }
+
+func (r *Recursion) Accepts(v any, jt JsumType) float64 { return r.Target.Accepts(v, jt) }
+
+func (r *Recursion) Example(v any, jt JsumType, _ float64) Deducer {
+	if jt.t == JsonNull {
+		r.Count++
+		r.Null++
+		return r
+	}
+	if m := objSeq(v, jt); m != nil {
+		r.Count++
+		r.Target.Count++
+		r.Target.addExample(m, jt.v == jsonObjOrdered)
+		return r
+	}
+	if jt.t == JsonObject {
+		return newAnyFrom(r).Example(v, jt, UnknownAccept)
+	}
+	u := newUnion(r)
+	return u.Example(v, jt, UnknownAccept)
+}
+
+func (r *Recursion) Hash(dh DedupHash) uint64 {
+	hash := r.dedBase.startHash(JsonObject)
+	hash.WriteString("↻")
+	hash.WriteString(r.Path)
+	res := hash.Sum64()
+	dh.add(res, r)
+	return res
+}
+
+func (r *Recursion) Equal(d Deducer) bool {
+	b, ok := d.(*Recursion)
+	if !ok {
+		return false
+	}
+	return r.dedBase.Equal(&b.dedBase) && r.Path == b.Path
+}
+
+func (r *Recursion) JSONSchema(*SchemaOptions) any {
+	ref := jscmRef{Ref: "#"}
+	if n := r.Target.typeName; n != "" {
+		ref.Ref = "#/$defs/" + n
+	}
+	return nullable(ref, r.Null > 0)
+}
+
+func (r *Recursion) super() *dedBase { return &r.dedBase }
+
+// mergeRec merges d into r's target object if d is an object or a recursion
+// to the same target.
+func (r *Recursion) mergeRec(d Deducer) Deducer {
+	switch d := d.(type) {
+	case *Recursion:
+		if d.Target != r.Target {
+			break
+		}
+		r.Count += d.Count
+		r.Null += d.Null
+		return r
+	case *Object:
+		if d != r.Target {
+			r.Target.mergeObj(d)
+		}
+		r.Count += d.Count
+		r.Null += d.Null
+		return r
+	}
+	return unionOf(r, d)
+}
+
+// FoldRecursion finds objects in d that have the same members with the same
+// JSON types as an enclosing object. These nested objects are replaced with a
+// Recursion to the nearest such enclosing object and their examples are
+// merged into it. This stops tree-shaped data like nested comments or file
+// trees from being unrolled as deep as the data goes.
+func FoldRecursion(d Deducer) Deducer {
+	for {
+		var folds []recFold
+		d = foldRec(d, "$", nil, &folds, false)
+		if len(folds) == 0 {
+			return d
+		}
+		for _, f := range folds {
+			if !f.dup {
+				f.rec.Target.mergeObj(f.obj)
+			}
+		}
+	}
+}
+
+type recAnc struct {
+	obj  *Object
+	path string
+}
+
+type recFold struct {
+	rec *Recursion
+	obj *Object
+	// dup is set for tuple elements whose examples are also in Array.Elem
+	dup bool
+}
+
+func foldRec(d Deducer, path string, ancs []recAnc, folds *[]recFold, dup bool) Deducer {
+	switch d := d.(type) {
+	case *Object:
+		for i := len(ancs) - 1; i >= 0; i-- {
+			if a := ancs[i]; sameMembers(a.obj, d) {
+				r := &Recursion{
+					dedBase: dedBase{cfg: d.cfg, Count: d.Count, Null: d.Null},
+					Path:    a.path,
+					Target:  a.obj,
+				}
+				*folds = append(*folds, recFold{rec: r, obj: d, dup: dup})
+				return r
+			}
+		}
+		foldMembers(d, path, ancs, folds, dup)
+	case *Array:
+		d.Elem = foldRec(d.Elem, path+"[*]", ancs, folds, dup)
+		for i, t := range d.Tuple {
+			d.Tuple[i] = foldRec(t, path+"["+strconv.Itoa(i)+"]", ancs, folds, true)
+		}
+	case *Map:
+		d.Value = foldRec(d.Value, path+".*", ancs, folds, dup)
+	case *Union:
+		for i, v := range d.Variants {
+			d.Variants[i] = foldRec(v, path, ancs, folds, dup)
+		}
+	case *Tagged:
+		for _, v := range d.Variants {
+			if o, ok := v.Ded.(*Object); ok {
+				foldMembers(o, path, ancs, folds, dup)
+			}
+		}
+	case *Grouped:
+		for _, k := range d.GroupKeys() {
+			d.Groups[k] = foldRec(d.Groups[k], path, ancs, folds, dup)
+		}
+	}
+	return d
+}
+
+func foldMembers(o *Object, path string, ancs []recAnc, folds *[]recFold, dup bool) {
+	ancs = append(ancs, recAnc{obj: o, path: path})
+	for _, n := range slices.Sorted(maps.Keys(o.Members)) {
+		m := o.Members[n]
+		m.Ded = foldRec(m.Ded, path+"."+n, ancs, folds, dup)
+		o.Members[n] = m
+	}
+}
+
+func sameMembers(a, b *Object) bool {
+	if len(a.Members) == 0 || len(a.Members) != len(b.Members) {
+		return false
+	}
+	for n, am := range a.Members {
+		bm, ok := b.Members[n]
+		if !ok || am.Ded.JsonType() != bm.Ded.JsonType() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFoldRecursion(t *testing.T) {
+	node := func(name string, children ...any) map[string]any {
+		return map[string]any{"name": name, "children": children}
+	}
+	v := node("root",
+		node("a", node("a1", node("a11"))),
+		node("b"),
+	)
+	cfg := testCfg
+	cfg.Array.MaxTuple = 4
+	d := FoldRecursion(Deduce(&cfg, v))
+	o, ok := d.(*Object)
+	if !ok {
+		t.Fatalf("folded to %T", d)
+	}
+	if o.Count != 5 {
+		t.Errorf("unexpected count %d", o.Count)
+	}
+	if m := o.Members["children"]; m.Occurence != 5 || m.Empties != 2 {
+		t.Errorf("unexpected children %d / empty %d", m.Occurence, m.Empties)
+	}
+	arr := o.Members["children"].Ded.(*Array)
+	r, ok := arr.Elem.(*Recursion)
+	if !ok {
+		t.Fatalf("children elements are %T", arr.Elem)
+	}
+	if r.Target != o || r.Path != "$" || r.Count != 4 {
+		t.Errorf("unexpected recursion to %s [%d×]", r.Path, r.Count)
+	}
+	if s := o.Members["name"].Ded.(*String); len(s.Stats) != 5 {
+		t.Errorf("unexpected names %v", s.Stats)
+	}
+
+	scm, err := Schema(d, SchemaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(scm)
+	js := string(data)
+	if !strings.Contains(js, `"items":{"$ref":"#/$defs/Root"}`) ||
+		scm["$ref"] != "#/$defs/Root" {
+		t.Errorf("no recursive reference in %s", js)
+	}
+}
+
+func TestFoldRecursion_nested(t *testing.T) {
+	v := map[string]any{
+		"id": 1.0,
+		"comment": map[string]any{
+			"text": "a",
+			"reply": map[string]any{
+				"text":  "b",
+				"reply": map[string]any{"text": "c", "reply": nil},
+			},
+		},
+	}
+	d := FoldRecursion(Deduce(&testCfg, v))
+	c := d.(*Object).Members["comment"].Ded.(*Object)
+	r, ok := c.Members["reply"].Ded.(*Recursion)
+	if !ok {
+		t.Fatalf("reply is %T", c.Members["reply"].Ded)
+	}
+	if r.Path != "$.comment" || r.Target != c {
+		t.Errorf("unexpected recursion to %s", r.Path)
+	}
+	if c.Count != 3 || r.Count != 3 || r.Null != 1 {
+		t.Errorf("unexpected counts comment:%d reply:%d/%d", c.Count, r.Count, r.Null)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Draft is the JSON Schema version that Schema generates.
+type Draft int
+
+const (
+	Draft2020_12 Draft = iota
+	Draft2019_09
+	Draft07
+)
+
+var draftNames = []string{
+	Draft2020_12: "2020-12",
+	Draft2019_09: "2019-09",
+	Draft07:      "07",
+}
+
+var draftURIs = []string{
+	Draft2020_12: "https://json-schema.org/draft/2020-12/schema",
+	Draft2019_09: "https://json-schema.org/draft/2019-09/schema",
+	Draft07:      "http://json-schema.org/draft-07/schema#",
+}
+
+func (d Draft) String() string {
+	if d >= 0 && int(d) < len(draftNames) {
+		return draftNames[d]
+	}
+	return fmt.Sprintf("Draft(%d)", int(d))
+}
+
+// URI returns the meta-schema URI of the draft that is used for "$schema".
+func (d Draft) URI() string { return draftURIs[d] }
+
+func (d Draft) MarshalText() ([]byte, error) {
+	if d < 0 || int(d) >= len(draftNames) {
+		return nil, fmt.Errorf("invalid draft %d", int(d))
+	}
+	return []byte(draftNames[d]), nil
+}
+
+// UnmarshalText reads one of the drafts 2020-12, 2019-09 or 07.
+func (d *Draft) UnmarshalText(text []byte) error {
+	for i, n := range draftNames {
+		if n == string(text) {
+			*d = Draft(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown JSON Schema draft '%s'", text)
+}
+
+// Strictness selects how closely a schema follows the observed values.
+type Strictness int
+
+const (
+	// SchemaObserved emits the types with the observed bounds, e.g. minimum
+	// and maximum of numbers or lengths of strings and arrays.
+	SchemaObserved Strictness = iota
+
+	// SchemaLoose only emits types and required members.
+	SchemaLoose
+
+	// SchemaStrict is like SchemaObserved and additionally closes objects
+	// and emits enums, patterns and formats for strings.
+	SchemaStrict
+)
+
+var strictnessNames = []string{
+	SchemaObserved: "observed",
+	SchemaLoose:    "loose",
+	SchemaStrict:   "strict",
+}
+
+func (s Strictness) String() string {
+	if s >= 0 && int(s) < len(strictnessNames) {
+		return strictnessNames[s]
+	}
+	return fmt.Sprintf("Strictness(%d)", int(s))
+}
+
+func (s Strictness) MarshalText() ([]byte, error) {
+	if s < 0 || int(s) >= len(strictnessNames) {
+		return nil, fmt.Errorf("invalid strictness %d", int(s))
+	}
+	return []byte(strictnessNames[s]), nil
+}
+
+// UnmarshalText reads one of: observed, loose, strict.
+func (s *Strictness) UnmarshalText(text []byte) error {
+	for i, n := range strictnessNames {
+		if n == string(text) {
+			*s = Strictness(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown schema strictness '%s'", text)
+}
+
+// SchemaOptions control the output of Schema.
+type SchemaOptions struct {
+	Draft      Draft      `json:"draft"`
+	Strictness Strictness `json:"strictness"`
+	// Annotate adds examples, counts as "x-jsum-count" and "x-jsum-null" and
+	// for members a description and the ratio of objects that have the
+	// member as "x-jsum-occurrence".
+	Annotate bool `json:"annotate"`
+	// ID and Title are written as "$id" and "title" of the schema if not
+	// empty.
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+// Schema returns the JSON Schema of d as generic JSON value. Reused types are
+// put into "$defs" ("definitions" for draft 07), see NameTypes. The type
+// names of d are the same as before the call.
+func Schema(d Deducer, opts SchemaOptions) (map[string]any, error) {
+	defer setTypeNames(typeNames(d))
+	defs := NameTypes(d)
+	// d itself is named if it is the target of a Recursion
+	root := map[string]any{"allOf": []any{schemaOf(d, &opts)}}
+	if len(defs) > 0 {
+		dm := make(map[string]any, len(defs))
+		for _, def := range defs {
+			d := def.merged()
+			dm[def.Name] = annotate(d, d.JSONSchema(&opts), &opts)
+		}
+		root["$defs"] = dm
+	}
+	data, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	var scm map[string]any
+	if err = json.Unmarshal(data, &scm); err != nil {
+		return nil, err
+	}
+	// Inline the root schema unless it is a reference that cannot have
+	// siblings in draft 07
+	if r, ok := scm["allOf"].([]any)[0].(map[string]any); ok &&
+		(r["$ref"] == nil || opts.Draft != Draft07) {
+		delete(scm, "allOf")
+		for k, v := range r {
+			scm[k] = v
+		}
+	}
+	if opts.Draft != Draft2020_12 {
+		downgrade(scm, opts.Draft)
+	}
+	scm["$schema"] = opts.Draft.URI()
+	if opts.ID != "" {
+		scm["$id"] = opts.ID
+	}
+	if opts.Title != "" {
+		scm["title"] = opts.Title
+	}
+	return scm, nil
+}
+
+// downgrade rewrites the draft 2020-12 schema s in place for an older draft.
+func downgrade(s any, draft Draft) {
+	walkSchema(s, func(scm map[string]any) {
+		if pis, ok := scm["prefixItems"]; ok {
+			delete(scm, "prefixItems")
+			if items, ok := scm["items"]; ok {
+				scm["additionalItems"] = items
+			}
+			scm["items"] = pis
+		}
+		if draft == Draft07 {
+			if ref, ok := scm["$ref"].(string); ok {
+				scm["$ref"] = strings.Replace(ref, "#/$defs/", "#/definitions/", 1)
+			}
+			if defs, ok := scm["$defs"]; ok {
+				delete(scm, "$defs")
+				scm["definitions"] = defs
+			}
+		}
+	})
+}
+
+// walkSchema calls f for the generic JSON schema s and then for all its
+// subschemas. Changes that f makes to the subschemas of s are walked. Only
+// keywords that are generated by JSONSchema methods are considered.
+func walkSchema(s any, f func(scm map[string]any)) {
+	scm, ok := s.(map[string]any)
+	if !ok {
+		return
+	}
+	f(scm)
+	for k, v := range scm {
+		switch k {
+		case "properties", "$defs", "definitions":
+			for _, p := range v.(map[string]any) {
+				walkSchema(p, f)
+			}
+		case "items", "prefixItems", "anyOf", "oneOf", "allOf":
+			if l, ok := v.([]any); ok {
+				for _, e := range l {
+					walkSchema(e, f)
+				}
+			} else {
+				walkSchema(v, f)
+			}
+		case "additionalItems", "additionalProperties", "propertyNames":
+			walkSchema(v, f)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func schemaJSON(t *testing.T, d Deducer, opts SchemaOptions) map[string]any {
+	t.Helper()
+	scm, err := Schema(d, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(scm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res map[string]any
+	if err = json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestSchema_nullable(t *testing.T) {
+	union := Config{Union: UnionConfig{Combine: []TypeSet{AllTypes}}}
+	tagged := Config{Union: UnionConfig{Tags: []string{"kind"}}}
+	tests := []struct {
+		name string
+		d    Deducer
+		key  string
+		want string
+	}{
+		{"string", deduceAll(&testCfg, "a", nil), "type", `["string","null"]`},
+		{"boolean", deduceAll(&testCfg, true), "type", `"boolean"`},
+		{"null only", deduceAll(&testCfg, nil), "type", `"null"`},
+		{"object", deduceAll(&testCfg, nil, map[string]any{"a": 1.0}),
+			"type", `["object","null"]`},
+		{"union", deduceAll(&union, "a", 1.0, nil),
+			"anyOf", `[{"type":"string","minLength":1,"maxLength":1},` +
+				`{"type":"integer","minimum":1,"maximum":1},{"type":"null"}]`},
+		{"tagged", deduceAll(&tagged, nil, map[string]any{"kind": "a"}),
+			"anyOf", `[{"oneOf":[{"type":"object","required":["kind"],` +
+				`"properties":{"kind":{"const":"a"}}}],` +
+				`"discriminator":{"propertyName":"kind"}},{"type":"null"}]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scm := schemaJSON(t, test.d, SchemaOptions{})
+			var want any
+			if err := json.Unmarshal([]byte(test.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := json.Marshal(scm[test.key])
+			exp, _ := json.Marshal(want)
+			if string(got) != string(exp) {
+				t.Errorf("%s is %s, want %s", test.key, got, exp)
+			}
+		})
+	}
+	if scm := schemaJSON(t, NewUnknown(&testCfg), SchemaOptions{}); len(scm) != 1 {
+		t.Errorf("unknown schema is not empty: %v", scm)
+	}
+}
+
+func TestSchema_drafts(t *testing.T) {
+	cfg := Config{Array: ArrayConfig{MaxTuple: 4}}
+	addr := map[string]any{"street": "s", "zip": "1"}
+	v := map[string]any{
+		"home":  addr,
+		"work":  addr,
+		"point": []any{1.0, "a"},
+	}
+	d := deduceAll(&cfg, v, v)
+	opts := SchemaOptions{ID: "https://example.com/s.json", Title: "Test"}
+	for _, test := range []struct {
+		draft            Draft
+		defs, ref, items string
+	}{
+		{Draft2020_12, "$defs", "#/$defs/Home", "prefixItems"},
+		{Draft2019_09, "$defs", "#/$defs/Home", "items"},
+		{Draft07, "definitions", "#/definitions/Home", "items"},
+	} {
+		t.Run(test.draft.String(), func(t *testing.T) {
+			opts.Draft = test.draft
+			scm := schemaJSON(t, d, opts)
+			if scm["$schema"] != test.draft.URI() ||
+				scm["$id"] != opts.ID ||
+				scm["title"] != opts.Title {
+				t.Errorf("unexpected header %v %v %v", scm["$schema"], scm["$id"], scm["title"])
+			}
+			if _, ok := scm[test.defs].(map[string]any)["Home"]; !ok {
+				t.Errorf("no %s in %v", test.defs, scm)
+			}
+			props := scm["properties"].(map[string]any)
+			if ref := props["work"].(map[string]any)["$ref"]; ref != test.ref {
+				t.Errorf("unexpected reference %v", ref)
+			}
+			point := props["point"].(map[string]any)
+			if l, ok := point[test.items].([]any); !ok || len(l) != 2 {
+				t.Errorf("no tuple in %s: %v", test.items, point)
+			}
+			if test.draft != Draft2020_12 && point["additionalItems"] != false {
+				t.Errorf("tuple allows additional items: %v", point)
+			}
+		})
+	}
+}
+
+func TestDraft_text(t *testing.T) {
+	for _, d := range []Draft{Draft2020_12, Draft2019_09, Draft07} {
+		txt, err := d.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r Draft
+		if err = r.UnmarshalText(txt); err != nil || r != d {
+			t.Errorf("draft %s read as %s: %v", d, r, err)
+		}
+	}
+	var d Draft
+	if err := d.UnmarshalText([]byte("04")); err == nil {
+		t.Error("no error for unknown draft")
+	}
+}
+
+func TestSchema_strictness(t *testing.T) {
+	cfg := Config{Array: ArrayConfig{MaxTuple: 4}}
+	var vs []any
+	for i, c := range []string{"red", "green", "red", "blue", "green", "red"} {
+		vs = append(vs, map[string]any{
+			"color": c,
+			"id":    fmt.Sprintf("%08x-0000-4000-8000-%012x", i, i),
+			"n":     float64(i),
+			"tags":  []any{"a", fmt.Sprint(i)},
+		})
+	}
+	d := deduceAll(&cfg, vs...)
+	keys := func(m any) []string {
+		return slices.Sorted(maps.Keys(m.(map[string]any)))
+	}
+	for _, test := range []struct {
+		strict                 Strictness
+		root, color, id, n, ts []string
+	}{
+		{SchemaLoose,
+			[]string{"$schema", "properties", "required", "type"},
+			[]string{"type"},
+			[]string{"type"},
+			[]string{"type"},
+			[]string{"prefixItems", "type"},
+		},
+		{SchemaObserved,
+			[]string{"$schema", "properties", "required", "type"},
+			[]string{"maxLength", "minLength", "type"},
+			[]string{"maxLength", "minLength", "type"},
+			[]string{"maximum", "minimum", "type"},
+			[]string{"items", "maxItems", "minItems", "prefixItems", "type", "uniqueItems"},
+		},
+		{SchemaStrict,
+			[]string{"$schema", "additionalProperties", "properties", "required", "type"},
+			[]string{"enum", "type"},
+			[]string{"format", "maxLength", "minLength", "pattern", "type"},
+			[]string{"maximum", "minimum", "type"},
+			[]string{"items", "maxItems", "minItems", "prefixItems", "type", "uniqueItems"},
+		},
+	} {
+		t.Run(test.strict.String(), func(t *testing.T) {
+			scm := schemaJSON(t, d, SchemaOptions{Strictness: test.strict})
+			if k := keys(scm); !slices.Equal(k, test.root) {
+				t.Errorf("root keywords %v", k)
+			}
+			props := scm["properties"].(map[string]any)
+			for n, exp := range map[string][]string{
+				"color": test.color,
+				"id":    test.id,
+				"n":     test.n,
+				"tags":  test.ts,
+			} {
+				if k := keys(props[n]); !slices.Equal(k, exp) {
+					t.Errorf("%s keywords %v", n, k)
+				}
+			}
+		})
+	}
+	scm := schemaJSON(t, d, SchemaOptions{Strictness: SchemaStrict})
+	color := scm["properties"].(map[string]any)["color"].(map[string]any)
+	if e := color["enum"].([]any); len(e) != 3 || e[0] != "blue" {
+		t.Errorf("unexpected enum %v", e)
+	}
+}
+
+func TestSchema_annotate(t *testing.T) {
+	d := deduceAll(&testCfg,
+		map[string]any{"s": "a", "n": 1.0},
+		map[string]any{"s": "b", "n": 5.0},
+		map[string]any{"s": "a", "n": nil},
+		map[string]any{"s": "c"},
+	)
+	scm := schemaJSON(t, d, SchemaOptions{Annotate: true})
+	if scm["x-jsum-count"] != 4.0 {
+		t.Errorf("unexpected root count %v", scm["x-jsum-count"])
+	}
+	props := scm["properties"].(map[string]any)
+	s := props["s"].(map[string]any)
+	if e, _ := json.Marshal(s["examples"]); string(e) != `["a","b","c"]` {
+		t.Errorf("unexpected string examples %s", e)
+	}
+	n := props["n"].(map[string]any)
+	if e, _ := json.Marshal(n["examples"]); string(e) != `[1,5]` {
+		t.Errorf("unexpected number examples %s", e)
+	}
+	if d := n["description"]; d != "present in 75% of objects, null in 25%" {
+		t.Errorf("unexpected description '%v'", d)
+	}
+	if n["x-jsum-occurrence"] != 0.75 || n["x-jsum-null"] != 1.0 {
+		t.Errorf("unexpected occurrence %v, null %v", n["x-jsum-occurrence"], n["x-jsum-null"])
+	}
+	if scm := schemaJSON(t, d, SchemaOptions{}); scm["x-jsum-count"] != nil {
+		t.Error("annotations without SchemaOptions.Annotate")
+	}
+}
+
+func TestSchema_defsAllOccurrences(t *testing.T) {
+	var v any
+	json.Unmarshal([]byte(`{
+		"home": {"zip": "1", "n": 1},
+		"work": {"zip": "123456", "n": 99}
+	}`), &v)
+	d := Deduce(&testCfg, v)
+	scm, err := Schema(d, SchemaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := scm["$defs"].(map[string]any)["Home"].(map[string]any)
+	props := def["properties"].(map[string]any)
+	zip := props["zip"].(map[string]any)
+	if zip["minLength"] != 1.0 || zip["maxLength"] != 6.0 {
+		t.Errorf("unexpected zip schema %v", zip)
+	}
+	n := props["n"].(map[string]any)
+	if n["minimum"] != 1.0 || n["maximum"] != 99.0 {
+		t.Errorf("unexpected n schema %v", n)
+	}
+	home := d.(*Object).Members["home"].Ded.(*Object)
+	if nr := home.Members["n"].Ded.(*Number); nr.Max != 1 {
+		t.Errorf("schema changed the deducer: max=%v", nr.Max)
+	}
+}
+
+func TestSchema_draftKeywords(t *testing.T) {
+	cfg := Config{
+		Union: UnionConfig{
+			MergeRejectMax: 0.5,
+			Combine:        []TypeSet{AllTypes},
+			Tags:           []string{"kind"},
+		},
+		Array: ArrayConfig{MaxTuple: 4},
+	}
+	var v1, v2 any
+	json.Unmarshal([]byte(`{"point":[1,"x"],"opt":null,"mixed":[1,"a",null],
+		"home":{"zip":"1"},"work":{"zip":"22"},
+		"events":[{"kind":"click","x":1},{"kind":"view","page":"p"}],
+		"tree":{"name":"r","children":[{"name":"c","children":[]}]}}`), &v1)
+	json.Unmarshal([]byte(`{"point":[2,"y"],"opt":"o","mixed":[{"a":1}],
+		"home":{"zip":"333"},"work":{"zip":"4444"},
+		"events":[{"kind":"click","x":2}],
+		"tree":{"name":"q","children":[]}}`), &v2)
+	d := Discriminate(FoldRecursion(Compact(deduceAll(&cfg, v1, v2))))
+	// Keywords that the generator must not emit for a draft
+	foreign := map[Draft][]string{
+		Draft2020_12: {"definitions", "additionalItems"},
+		Draft2019_09: {"definitions", "prefixItems"},
+		Draft07:      {"$defs", "prefixItems"},
+	}
+	for _, draft := range []Draft{Draft2020_12, Draft2019_09, Draft07} {
+		for _, strict := range []Strictness{SchemaObserved, SchemaLoose, SchemaStrict} {
+			t.Run(fmt.Sprintf("%s/%s", draft, strict), func(t *testing.T) {
+				scm := schemaJSON(t, d, SchemaOptions{Draft: draft, Strictness: strict})
+				walkSchema(scm, func(s map[string]any) {
+					for _, k := range foreign[draft] {
+						if _, ok := s[k]; ok {
+							t.Errorf("%s in %v", k, s)
+						}
+					}
+					checkSchemaType(t, s["type"])
+					if ref, ok := s["$ref"].(string); ok && !resolves(scm, ref) {
+						t.Errorf("unresolved reference '%s'", ref)
+					}
+				})
+			})
+		}
+	}
+	doc, err := OpenAPI(d, SchemaOptions{Strictness: SchemaStrict})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(doc)
+	json.Unmarshal(data, &doc)
+	for _, s := range doc["components"].(map[string]any)["schemas"].(map[string]any) {
+		walkSchema(s, func(s map[string]any) {
+			if ref, ok := s["$ref"].(string); ok && !resolves(doc, ref) {
+				t.Errorf("unresolved OpenAPI reference '%s'", ref)
+			}
+		})
+	}
+}
+
+// checkSchemaType checks that typ is a simple type or a list of distinct
+// simple types, i.e. nullable types are no lists of schemas.
+func checkSchemaType(t *testing.T, typ any) {
+	t.Helper()
+	simple := []string{"array", "boolean", "integer", "null", "number", "object", "string"}
+	switch typ := typ.(type) {
+	case nil:
+	case string:
+		if !slices.Contains(simple, typ) {
+			t.Errorf("unknown type '%s'", typ)
+		}
+	case []any:
+		seen := make(map[any]bool)
+		for _, e := range typ {
+			if s, ok := e.(string); !ok || !slices.Contains(simple, s) || seen[e] {
+				t.Errorf("invalid type list %v", typ)
+			}
+			seen[e] = true
+		}
+	default:
+		t.Errorf("invalid type %v", typ)
+	}
+}
+
+// resolves reports whether the local reference ref points into doc.
+func resolves(doc map[string]any, ref string) bool {
+	ptr, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return false
+	}
+	var v any = doc
+	for _, tok := range strings.Split(ptr, "/")[1:] {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		if v, ok = m[tok]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"slices"
+	"strings"
+)
+
+// Shape is a set of members that were present together in objects.
+type Shape struct {
+	Members []string
+	Count   int
+}
+
+const shapeSep = "\x1f"
+
+func shapeMembers(sig string) []string {
+	if sig == "" {
+		return nil
+	}
+	return strings.Split(sig, shapeSep)
+}
+
+func (o *Object) addShape(sig string, n int) {
+	if o.cfg == nil || o.cfg.Object.MaxShapes <= 0 {
+		return
+	}
+	if o.Shapes == nil {
+		o.Shapes = make(map[string]int)
+	}
+	if _, ok := o.Shapes[sig]; ok || len(o.Shapes) < o.cfg.Object.MaxShapes {
+		o.Shapes[sig] += n
+	} else {
+		o.ShapeOverflow += n
+	}
+}
+
+func (o *Object) addOrder(sig string, n int) {
+	if o.cfg == nil || o.cfg.Object.MaxOrders <= 0 {
+		return
+	}
+	if o.Orders == nil {
+		o.Orders = make(map[string]int)
+	}
+	if _, ok := o.Orders[sig]; ok || len(o.Orders) < o.cfg.Object.MaxOrders {
+		o.Orders[sig] += n
+	} else {
+		o.OrderOverflow += n
+	}
+}
+
+// OrderList returns the observed member orders of o ordered by descending
+// count.
+func (o *Object) OrderList() []Shape {
+	return shapeList(o.Orders)
+}
+
+// MemberOrder returns one order of all members in the tracked member orders
+// that is consistent with each of them. If no such order exists, consistent
+// is false and order is nil.
+func (o *Object) MemberOrder() (order []string, consistent bool) {
+	succ := make(map[string][]string)
+	pred := make(map[string]int)
+	for sig := range o.Orders {
+		ms := shapeMembers(sig)
+		for i, m := range ms {
+			if _, ok := pred[m]; !ok {
+				pred[m] = 0
+			}
+			if i > 0 && !slices.Contains(succ[ms[i-1]], m) {
+				succ[ms[i-1]] = append(succ[ms[i-1]], m)
+				pred[m]++
+			}
+		}
+	}
+	var ready []string
+	for m, n := range pred {
+		if n == 0 {
+			ready = append(ready, m)
+		}
+	}
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		m := ready[0]
+		ready = ready[1:]
+		order = append(order, m)
+		for _, s := range succ[m] {
+			if pred[s]--; pred[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+	}
+	if len(order) < len(pred) {
+		return nil, false
+	}
+	return order, true
+}
+
+// ShapeList returns the observed shapes of o ordered by descending count.
+func (o *Object) ShapeList() []Shape {
+	return shapeList(o.Shapes)
+}
+
+func shapeList(shapes map[string]int) []Shape {
+	res := make([]Shape, 0, len(shapes))
+	for sig, n := range shapes {
+		res = append(res, Shape{Members: shapeMembers(sig), Count: n})
+	}
+	slices.SortFunc(res, func(a, b Shape) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return slices.Compare(a.Members, b.Members)
+	})
+	return res
+}
+
+// CoOccurence is the result of analysing the shapes of an object.
+type CoOccurence struct {
+	// Groups of optional members that always occur together. Groups are
+	// ordered by their first member name.
+	Groups [][]string
+	// Exclusive are pairs of indices into Groups of member groups that never
+	// occur together.
+	Exclusive [][2]int
+	// Complete is false if not all shapes could be tracked. Then the analysis
+	// is based on a subset of the objects.
+	Complete bool
+}
+
+// CoOccurence analyses which optional members of o always or never occur
+// together. Mandatory members and members that were never tracked in a shape
+// are not considered.
+func (o *Object) CoOccurence() (res CoOccurence) {
+	res.Complete = o.ShapeOverflow == 0
+	shapes := o.ShapeList()
+	if len(shapes) < 2 {
+		return res
+	}
+	pres := make(map[string][]byte)
+	for i, sh := range shapes {
+		for _, m := range sh.Members {
+			p := pres[m]
+			if p == nil {
+				p = make([]byte, len(shapes))
+				pres[m] = p
+			}
+			p[i] = 1
+		}
+	}
+	groups := make(map[string][]string)
+	for m, p := range pres {
+		if !slices.Contains(p, 0) {
+			continue
+		}
+		groups[string(p)] = append(groups[string(p)], m)
+	}
+	sigs := make([]string, 0, len(groups))
+	for sig, g := range groups {
+		slices.Sort(g)
+		sigs = append(sigs, sig)
+	}
+	slices.SortFunc(sigs, func(a, b string) int {
+		return strings.Compare(groups[a][0], groups[b][0])
+	})
+	for i, si := range sigs {
+		res.Groups = append(res.Groups, groups[si])
+	NEXT_GROUP:
+		for j := i + 1; j < len(sigs); j++ {
+			sj := sigs[j]
+			for k := range len(si) {
+				if si[k] == 1 && sj[k] == 1 {
+					continue NEXT_GROUP
+				}
+			}
+			res.Exclusive = append(res.Exclusive, [2]int{i, j})
+		}
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestObject_CoOccurence(t *testing.T) {
+	cfg := Config{Object: ObjectConfig{MaxShapes: 8}}
+	var d Deducer = NewUnknown(&cfg)
+	for _, v := range []map[string]any{
+		{"id": 1, "x": 1, "y": 2},
+		{"id": 2, "z": "q"},
+		{"id": 3, "x": 1, "y": 3, "w": true},
+		{"id": 4, "z": "q"},
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	o, ok := d.(*Object)
+	if !ok {
+		t.Fatalf("deduced not object but %T", d)
+	}
+	if sl := o.ShapeList(); len(sl) != 3 {
+		t.Fatalf("unexpected shapes %v", sl)
+	} else if sl[0].Count != 2 || !slices.Equal(sl[0].Members, []string{"id", "z"}) {
+		t.Errorf("unexpected most common shape %v", sl[0])
+	}
+	co := o.CoOccurence()
+	if !co.Complete {
+		t.Error("co-occurence not complete")
+	}
+	expGroups := [][]string{{"w"}, {"x", "y"}, {"z"}}
+	if !slices.EqualFunc(co.Groups, expGroups, slices.Equal) {
+		t.Errorf("unexpected groups %v", co.Groups)
+	}
+	expExcl := [][2]int{{0, 2}, {1, 2}}
+	if !slices.Equal(co.Exclusive, expExcl) {
+		t.Errorf("unexpected exclusive groups %v", co.Exclusive)
+	}
+}
+
+func TestCoOccurenceLabels(t *testing.T) {
+	co := CoOccurence{
+		Groups:    [][]string{{"x", "y"}, {"z"}},
+		Exclusive: [][2]int{{0, 1}},
+		Complete:  true,
+	}
+	exp := []string{"always together: x, y", "never together: {x, y} / {z}"}
+	if ls := CoOccurenceLabels(co); !slices.Equal(ls, exp) {
+		t.Errorf("unexpected labels %q", ls)
+	}
+	co.Complete = false
+	exp = []string{
+		"always together in tracked shapes: x, y",
+		"never together in tracked shapes: {x, y} / {z}",
+	}
+	if ls := CoOccurenceLabels(co); !slices.Equal(ls, exp) {
+		t.Errorf("unexpected partial labels %q", ls)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// TypeCluster is a group of objects at different paths that are similar
+// but not equal.
+type TypeCluster struct {
+	Paths   []string
+	Objects []*Object
+	// Similarity is the minimum Similarity of all pairs of objects.
+	Similarity float64
+	// Diffs are the members that are not in all objects with the same type.
+	Diffs []MemberDiff
+}
+
+// MemberDiff describes a member that differs between the objects of a
+// TypeCluster.
+type MemberDiff struct {
+	// Name is the member name. Members of nested objects have the dotted
+	// names of the enclosing members as prefix, e.g. "addr.zip".
+	Name string
+	// Types has the JSON type of the member for each object of the cluster.
+	// Zero means the object does not have the member.
+	Types []JsonType
+}
+
+// SimilarTypes finds clusters of objects in d where all pairs of objects
+// have a Similarity of at least minSim. Clusters of equal objects are not
+// reported. Object variants of a union have the same path, they are told
+// apart by a suffix with the variant's number, e.g. "$.x (variant 2)". The
+// clusters are ordered by their first path.
+func SimilarTypes(d Deducer, minSim float64) (res []TypeCluster) {
+	var (
+		objs  []*Object
+		paths []string
+	)
+	pathNo := make(map[string]int)
+	walkTypes(d, "$", "", func(d Deducer, path, _ string) {
+		if o, ok := d.(*Object); ok && len(o.Members) > 0 {
+			objs = append(objs, o)
+			paths = append(paths, path)
+			pathNo[path]++
+		}
+	})
+	variant := make(map[string]int)
+	for i, p := range paths {
+		if pathNo[p] > 1 {
+			variant[p]++
+			paths[i] = fmt.Sprintf("%s (variant %d)", p, variant[p])
+		}
+	}
+NEXT_OBJECT:
+	for i, o := range objs {
+		for c := range res {
+			cl := &res[c]
+			sim := cl.Similarity
+			for _, co := range cl.Objects {
+				if sim = min(sim, Similarity(o, co)); sim < minSim {
+					break
+				}
+			}
+			if sim >= minSim {
+				cl.Objects = append(cl.Objects, o)
+				cl.Paths = append(cl.Paths, paths[i])
+				cl.Similarity = sim
+				continue NEXT_OBJECT
+			}
+		}
+		res = append(res, TypeCluster{
+			Paths:      []string{paths[i]},
+			Objects:    []*Object{o},
+			Similarity: 1,
+		})
+	}
+	for i := range res {
+		if len(res[i].Objects) > 1 {
+			res[i].Diffs = memberDiffs(res[i].Objects, "")
+		}
+	}
+	return slices.DeleteFunc(res, func(cl TypeCluster) bool {
+		return len(cl.Diffs) == 0
+	})
+}
+
+// memberDiffs compares the members of objs. Members that are objects in all
+// objs are compared recursively with prefix plus the member name.
+func memberDiffs(objs []*Object, prefix string) (res []MemberDiff) {
+	names := make(map[string]bool)
+	for _, o := range objs {
+		for n := range o.Members {
+			names[n] = true
+		}
+	}
+	for _, n := range slices.Sorted(maps.Keys(names)) {
+		diff := MemberDiff{Name: prefix + n, Types: make([]JsonType, len(objs))}
+		nested := make([]*Object, 0, len(objs))
+		differs := false
+		for i, o := range objs {
+			if m, ok := o.Members[n]; ok {
+				diff.Types[i] = m.Ded.JsonType()
+				if no, ok := m.Ded.(*Object); ok {
+					nested = append(nested, no)
+				}
+			}
+			differs = differs || diff.Types[i] != diff.Types[0]
+		}
+		switch {
+		case differs:
+			res = append(res, diff)
+		case len(nested) == len(objs):
+			res = append(res, memberDiffs(nested, diff.Name+".")...)
+		}
+	}
+	return res
+}
+
+// Label returns the member name and in how many objects it occurs with
+// which type, e.g. "zip: string in 3, missing in 1".
+func (md *MemberDiff) Label() string {
+	counts := make(map[JsonType]int)
+	var order []JsonType
+	for _, t := range md.Types {
+		if counts[t] == 0 {
+			order = append(order, t)
+		}
+		counts[t]++
+	}
+	var sb strings.Builder
+	sb.WriteString(md.Name)
+	for i, t := range order {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString(", ")
+		}
+		if t == 0 {
+			fmt.Fprintf(&sb, "missing in %d", counts[t])
+		} else {
+			fmt.Fprintf(&sb, "%s in %d", t, counts[t])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSimilarity_objects(t *testing.T) {
+	obj := func(v map[string]any) *Object { return Deduce(&testCfg, v).(*Object) }
+	a := obj(map[string]any{"id": 1.0, "name": "a", "addr": map[string]any{"zip": "1", "city": "x"}})
+	b := obj(map[string]any{"id": "1", "name": "b", "addr": map[string]any{"zip": "2"}})
+	// id: 0, name: 1, addr: 1/2
+	if s := Similarity(a, b); math.Abs(s-0.5) > 1e-9 {
+		t.Errorf("unexpected similarity %f", s)
+	}
+	if s := Similarity(a, a); s != 1 {
+		t.Errorf("unexpected self similarity %f", s)
+	}
+
+	cfg := testCfg
+	cfg.Union.AcceptAnyType = true
+	a, b = Deduce(&cfg, map[string]any{"id": 1.0}).(*Object), Deduce(&cfg, map[string]any{"id": "1"}).(*Object)
+	if s := Similarity(a, b); s != 1 {
+		t.Errorf("unexpected similarity %f with any type", s)
+	}
+	cfg.Union.AcceptAnyType = false
+	cfg.Union.Accept = AcceptStrict
+	a = Deduce(&cfg, map[string]any{"id": 1.0, "x": 1.0}).(*Object)
+	b = Deduce(&cfg, map[string]any{"id": 1.0}).(*Object)
+	if s := Similarity(a, b); s != 0 {
+		t.Errorf("unexpected strict similarity %f", s)
+	}
+}
+
+func TestSimilarTypes(t *testing.T) {
+	d := Deduce(&testCfg, map[string]any{
+		"customer": map[string]any{"id": 1.0, "name": "a", "email": "x"},
+		"agent":    map[string]any{"id": 2.0, "name": "b", "email": "y", "phone": "z"},
+		"staff":    map[string]any{"id": 3.0, "name": "c", "email": "z"},
+		"meta":     map[string]any{"version": 1.0},
+	})
+	cls := SimilarTypes(d, 0.7)
+	if len(cls) != 1 {
+		t.Fatalf("found %d clusters", len(cls))
+	}
+	cl := cls[0]
+	if !slices.Equal(cl.Paths, []string{"$.agent", "$.customer", "$.staff"}) {
+		t.Errorf("unexpected paths %v", cl.Paths)
+	}
+	if cl.Similarity != 0.75 {
+		t.Errorf("unexpected similarity %f", cl.Similarity)
+	}
+	if len(cl.Diffs) != 1 {
+		t.Fatalf("unexpected diffs %v", cl.Diffs)
+	}
+	if l := cl.Diffs[0].Label(); l != "phone: string in 1, missing in 2" {
+		t.Errorf("unexpected diff label '%s'", l)
+	}
+}
+
+func TestSimilarTypes_nested(t *testing.T) {
+	addr := func(zip any) map[string]any {
+		return map[string]any{"street": "s", "zip": zip}
+	}
+	d := Deduce(&testCfg, map[string]any{
+		"home": map[string]any{"name": "a", "addr": addr("1")},
+		"work": map[string]any{"name": "b", "addr": addr(1.0)},
+	})
+	cls := SimilarTypes(d, 0.5)
+	if len(cls) != 2 {
+		t.Fatalf("found %d clusters", len(cls))
+	}
+	for i, exp := range []struct {
+		paths []string
+		label string
+	}{
+		{[]string{"$.home", "$.work"}, "addr.zip: string in 1, number in 1"},
+		{[]string{"$.home.addr", "$.work.addr"}, "zip: string in 1, number in 1"},
+	} {
+		if !slices.Equal(cls[i].Paths, exp.paths) {
+			t.Errorf("unexpected paths %v", cls[i].Paths)
+		}
+		if len(cls[i].Diffs) != 1 {
+			t.Fatalf("unexpected diffs %v", cls[i].Diffs)
+		}
+		if l := cls[i].Diffs[0].Label(); l != exp.label {
+			t.Errorf("unexpected diff label '%s'", l)
+		}
+	}
+
+	cfg := Config{Union: UnionConfig{
+		MergeRejectMax: 0.9,
+		Combine:        []TypeSet{AllTypes},
+	}}
+	d = deduceAll(&cfg,
+		map[string]any{"id": 1.0, "name": "a", "mail": "x"},
+		map[string]any{"id": 2.0, "name": "b", "phone": "y"},
+	)
+	cls = SimilarTypes(d, 0.4)
+	if len(cls) != 1 {
+		t.Fatalf("found %d clusters in union", len(cls))
+	}
+	if !slices.Equal(cls[0].Paths, []string{"$ (variant 1)", "$ (variant 2)"}) {
+		t.Errorf("unexpected union paths %v", cls[0].Paths)
+	}
+}
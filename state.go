@@ -23,6 +23,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"maps"
 	"math"
 	"slices"
 	"strconv"
@@ -32,7 +33,7 @@ import (
 	"git.fractalqb.de/fractalqb/eloc/must"
 )
 
-const StateVersion = 0
+const StateVersion = 13
 
 const (
 	tidInvalid byte = iota
@@ -44,6 +45,10 @@ const (
 	tidArray
 	tidUnion
 	tidAny
+	tidMap
+	tidTagged
+	tidGrouped
+	tidRecursion
 )
 
 type StateIO struct {
@@ -53,12 +58,14 @@ type StateIO struct {
 	wr   io.Writer
 	rd   restCountReader
 	cfg  *Config
+	// objs are the enclosing objects to resolve Recursion targets
+	objs []*Object
 
 	StrCount, StrDup int
 }
 
 func (sio *StateIO) WriteState(w io.Writer, ded Deducer) (err error) {
-	must.RecoverAs(&err, "write jsum state")
+	defer must.RecoverAs(&err, "write jsum state")
 	must.RetCtx(fmt.Fprintf(w, "JSUM%d\n", StateVersion)).Msg("header")
 	if sio.strs == nil {
 		sio.strs = make(map[string]int64)
@@ -73,7 +80,7 @@ func (sio *StateIO) WriteState(w io.Writer, ded Deducer) (err error) {
 }
 
 func (sio *StateIO) ReadState(r io.Reader, cfg *Config, size int64) (_ Deducer, err error) {
-	must.RecoverAs(&err, "read jsum state")
+	defer must.RecoverAs(&err, "read jsum state")
 	sio.rd = restCountReader{bufio.NewReader(r), size}
 	sio.cfg = cfg
 	defer func() {
@@ -102,6 +109,14 @@ func (sio *StateIO) wrDed(ded Deducer) {
 		sio.wrDedObj(ded)
 	case *Array:
 		sio.wrDedArray(ded)
+	case *Map:
+		sio.wrDedMap(ded)
+	case *Tagged:
+		sio.wrDedTagged(ded)
+	case *Grouped:
+		sio.wrDedGrouped(ded)
+	case *Recursion:
+		sio.wrDedRecursion(ded)
 	case *Union:
 		sio.wrDedUnion(ded)
 	case *Any:
@@ -128,6 +143,14 @@ func (sio *StateIO) rdDed() Deducer {
 		return sio.rdDedObj()
 	case tidArray:
 		return sio.rdDedArray()
+	case tidMap:
+		return sio.rdDedMap()
+	case tidTagged:
+		return sio.rdDedTagged()
+	case tidGrouped:
+		return sio.rdDedGrouped()
+	case tidRecursion:
+		return sio.rdDedRecursion()
 	case tidUnion:
 		return sio.rdDedUnion()
 	case tidAny:
@@ -135,7 +158,7 @@ func (sio *StateIO) rdDed() Deducer {
 	case tidUnknown:
 		return sio.rdDedUnk()
 	}
-	return newInvalid(eloc.Errorf("illegal deducer type id: %d", tid))
+	panic(eloc.Errorf("illegal deducer type id: %d", tid))
 }
 
 func (sio *StateIO) wrBase(tid byte, ded *dedBase) {
@@ -194,6 +217,9 @@ func (sio *StateIO) wrDedNum(ded *Number) {
 		flags |= 2
 	}
 	sio.buf = append(sio.buf, flags)
+	sio.buf = binary.AppendUvarint(sio.buf, ded.GCD)
+	sio.buf = binary.AppendVarint(sio.buf, int64(ded.Decimals))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.StepValues))
 	must.RetCtx(sio.wr.Write(sio.buf)).Msg("number deducer")
 }
 
@@ -205,6 +231,11 @@ func (sio *StateIO) rdDedNum() *Number {
 	flags := must.RetCtx(sio.rd.ReadByte()).Msg("number deducer flags")
 	ded.IsFloat = flags&1 != 0
 	ded.HasFrac = flags&2 != 0
+	ded.GCD = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("number deducer gcd")
+	tmp := must.RetCtx(binary.ReadVarint(&sio.rd)).Msg("number deducer decimals")
+	ded.Decimals = int(tmp)
+	u := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("number deducer step values")
+	ded.StepValues = int(u)
 	return ded
 }
 
@@ -227,16 +258,36 @@ func (sio *StateIO) rdDedBool() *Boolean {
 
 func (sio *StateIO) wrDedObj(ded *Object) {
 	sio.wrBase(tidObject, &ded.dedBase)
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.Duplicates))
 	sio.buf = binary.AppendUvarint(sio.buf, uint64(len(ded.Members)))
 	must.RetCtx(sio.wr.Write(sio.buf)).Msg("object member count")
+	sio.objs = append(sio.objs, ded)
 	for n, m := range ded.Members {
 		sio.wrMbr(n, m)
 	}
+	sio.objs = sio.objs[:len(sio.objs)-1]
+	sio.wrShapes(ded.Shapes, ded.ShapeOverflow, "shape")
+	sio.wrShapes(ded.Orders, ded.OrderOverflow, "order")
+}
+
+func (sio *StateIO) wrShapes(shapes map[string]int, overflow int, what string) {
+	sio.buf = binary.AppendUvarint(sio.buf[:0], uint64(len(shapes)))
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("object %s count", what)
+	for sig, n := range shapes {
+		sio.wrString(sig)
+		sio.buf = binary.AppendUvarint(sio.buf[:0], uint64(n))
+		must.RetCtx(sio.wr.Write(sio.buf)).Msg("object %s %q", what, sig)
+	}
+	sio.buf = binary.AppendUvarint(sio.buf[:0], uint64(overflow))
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("object %s overflow", what)
 }
 
 func (sio *StateIO) wrMbr(n string, m Member) {
 	sio.wrString(n)
 	sio.buf = binary.AppendUvarint(sio.buf[:0], uint64(m.Occurence))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(m.Duplicates))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(m.Nulls))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(m.Empties))
 	must.RetCtx(sio.wr.Write(sio.buf)).Msg("object member accurence")
 	sio.wrDed(m.Ded)
 }
@@ -244,26 +295,73 @@ func (sio *StateIO) wrMbr(n string, m Member) {
 func (sio *StateIO) rdDedObj() *Object {
 	ded := &Object{dedBase: dedBase{cfg: sio.cfg}}
 	sio.rdBase(&ded.dedBase)
+	u := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("object duplicates")
+	ded.Duplicates = int(u)
 	mno := must.RetCtx(binary.ReadUvarint(&sio.rd)).
 		Msg("object member count")
 	sio.rd.checkU(statMinMbrSz*mno, "object member count") // TODO factor N *varNo?
 	ded.Members = make(map[string]Member, mno)
+	sio.objs = append(sio.objs, ded)
 	for range mno {
 		n := sio.rdString()
 		occ := must.RetCtx(binary.ReadUvarint(&sio.rd)).
 			Msg("object member occurence")
+		dup := must.RetCtx(binary.ReadUvarint(&sio.rd)).
+			Msg("object member duplicates")
+		nulls := must.RetCtx(binary.ReadUvarint(&sio.rd)).
+			Msg("object member nulls")
+		empties := must.RetCtx(binary.ReadUvarint(&sio.rd)).
+			Msg("object member empties")
 		mded := sio.rdDed()
-		ded.Members[n] = Member{Occurence: int(occ), Ded: mded}
+		ded.Members[n] = Member{
+			Occurence:  int(occ),
+			Duplicates: int(dup),
+			Nulls:      int(nulls),
+			Empties:    int(empties),
+			Ded:        mded,
+		}
 	}
+	sio.objs = sio.objs[:len(sio.objs)-1]
+	ded.Shapes, ded.ShapeOverflow = sio.rdShapes("shape")
+	ded.Orders, ded.OrderOverflow = sio.rdShapes("order")
 	return ded
 }
 
+func (sio *StateIO) rdShapes(what string) (shapes map[string]int, overflow int) {
+	sno := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("object %s count", what)
+	sio.rd.checkU(statMinShapeSz*sno, "object %s count", what)
+	if sno > 0 {
+		shapes = make(map[string]int, sno)
+	}
+	for i := range sno {
+		sig := sio.rdString()
+		n := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("object %s %d", what, i)
+		shapes[sig] = int(n)
+	}
+	u := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("object %s overflow", what)
+	return shapes, int(u)
+}
+
 func (sio *StateIO) wrDedArray(ded *Array) {
 	sio.wrBase(tidArray, &ded.dedBase)
 	sio.buf = binary.AppendVarint(sio.buf, int64(ded.MinLen))
 	sio.buf = binary.AppendVarint(sio.buf, int64(ded.MaxLen))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(len(ded.Tuple)))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.Elems))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.Empty))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(len(ded.LenHist)))
+	for _, n := range ded.LenHist {
+		sio.buf = binary.AppendUvarint(sio.buf, uint64(n))
+	}
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.Scalars))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.UniqueNo))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.AscNo))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.DescNo))
 	must.RetCtx(sio.wr.Write(sio.buf)).Msg("array min and max len")
 	sio.wrDed(ded.Elem)
+	for _, t := range ded.Tuple {
+		sio.wrDed(t)
+	}
 }
 
 func (sio *StateIO) rdDedArray() *Array {
@@ -273,7 +371,63 @@ func (sio *StateIO) rdDedArray() *Array {
 	ded.MinLen = int(tmp)
 	tmp = must.RetCtx(binary.ReadVarint(&sio.rd)).Msg("read array max len")
 	ded.MaxLen = int(tmp)
+	tno := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array tuple len")
+	sio.rd.checkU(statMinVarSz*tno, "array tuple len")
+	u := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array elements")
+	ded.Elems = int(u)
+	u = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array empty")
+	ded.Empty = int(u)
+	hno := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array len hist")
+	if hno > 64 {
+		panic(eloc.Errorf("array len histogram with %d buckets", hno))
+	}
+	if hno > 0 {
+		ded.LenHist = make([]int, hno)
+		for i := range ded.LenHist {
+			u = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array len bucket %d", i)
+			ded.LenHist[i] = int(u)
+		}
+	}
+	u = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array scalars")
+	ded.Scalars = int(u)
+	u = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array unique")
+	ded.UniqueNo = int(u)
+	u = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array ascending")
+	ded.AscNo = int(u)
+	u = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read array descending")
+	ded.DescNo = int(u)
 	ded.Elem = sio.rdDed()
+	if tno > 0 {
+		ded.Tuple = make([]Deducer, tno)
+		for i := range ded.Tuple {
+			ded.Tuple[i] = sio.rdDed()
+		}
+	}
+	return ded
+}
+
+func (sio *StateIO) wrDedMap(ded *Map) {
+	sio.wrBase(tidMap, &ded.dedBase)
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.Entries))
+	sio.buf = binary.AppendVarint(sio.buf, int64(ded.MinLen))
+	sio.buf = binary.AppendVarint(sio.buf, int64(ded.MaxLen))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.Pattern))
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("map key stats")
+	sio.wrDed(ded.Value)
+}
+
+func (sio *StateIO) rdDedMap() *Map {
+	ded := &Map{dedBase: dedBase{cfg: sio.cfg}}
+	sio.rdBase(&ded.dedBase)
+	u := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("map entries")
+	ded.Entries = int(u)
+	tmp := must.RetCtx(binary.ReadVarint(&sio.rd)).Msg("map min key len")
+	ded.MinLen = int(tmp)
+	tmp = must.RetCtx(binary.ReadVarint(&sio.rd)).Msg("map max key len")
+	ded.MaxLen = int(tmp)
+	u = must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("map key pattern")
+	ded.Pattern = KeyPattern(u)
+	ded.Value = sio.rdDed()
 	return ded
 }
 
@@ -298,14 +452,124 @@ func (sio *StateIO) rdDedUnion() *Union {
 	return ded
 }
 
+func (sio *StateIO) wrDedTagged(ded *Tagged) {
+	sio.wrBase(tidTagged, &ded.dedBase)
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("tagged union")
+	sio.wrString(ded.Tag)
+	sio.buf = binary.AppendUvarint(sio.buf[:0], uint64(len(ded.Variants)))
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("tagged variants len")
+	for _, v := range ded.Variants {
+		sio.buf = binary.AppendUvarint(sio.buf[:0], uint64(len(v.Values)))
+		must.RetCtx(sio.wr.Write(sio.buf)).Msg("tag values len")
+		for _, val := range v.Values {
+			sio.wrString(val)
+		}
+		sio.wrDed(v.Ded)
+	}
+}
+
+func (sio *StateIO) rdDedTagged() *Tagged {
+	ded := &Tagged{dedBase: dedBase{cfg: sio.cfg}}
+	sio.rdBase(&ded.dedBase)
+	ded.Tag = sio.rdString()
+	varNo := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("tagged variant count")
+	sio.rd.checkU(statMinVarSz*varNo, "tagged variant count")
+	ded.Variants = make([]TagVariant, varNo)
+	for i := range varNo {
+		valNo := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("tag values len")
+		sio.rd.checkU(statMinStrLen*valNo, "tag values len")
+		v := &ded.Variants[i]
+		v.Values = make([]string, valNo)
+		for j := range valNo {
+			v.Values[j] = sio.rdString()
+		}
+		v.Ded = sio.rdDed()
+	}
+	return ded
+}
+
+func (sio *StateIO) wrDedGrouped(ded *Grouped) {
+	sio.wrBase(tidGrouped, &ded.dedBase)
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("grouped")
+	sio.wrString(ded.Path)
+	sio.buf = binary.AppendUvarint(sio.buf[:0], uint64(len(ded.Groups)))
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("group count")
+	for k, g := range ded.Groups {
+		sio.wrString(k)
+		sio.wrDed(g)
+	}
+}
+
+func (sio *StateIO) rdDedGrouped() *Grouped {
+	ded := &Grouped{dedBase: dedBase{cfg: sio.cfg}}
+	sio.rdBase(&ded.dedBase)
+	ded.Path = sio.rdString()
+	gno := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("group count")
+	sio.rd.checkU(statMinMbrSz*gno, "group count")
+	ded.Groups = make(map[string]Deducer, gno)
+	for range gno {
+		k := sio.rdString()
+		ded.Groups[k] = sio.rdDed()
+	}
+	return ded
+}
+
+func (sio *StateIO) wrDedRecursion(ded *Recursion) {
+	up := -1
+	for i := len(sio.objs) - 1; i >= 0; i-- {
+		if sio.objs[i] == ded.Target {
+			up = len(sio.objs) - 1 - i
+			break
+		}
+	}
+	if up < 0 {
+		panic(eloc.Errorf("recursion to %s without enclosing target", ded.Path))
+	}
+	sio.wrBase(tidRecursion, &ded.dedBase)
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(up))
+	must.RetCtx(sio.wr.Write(sio.buf)).Msg("recursion")
+	sio.wrString(ded.Path)
+}
+
+func (sio *StateIO) rdDedRecursion() *Recursion {
+	ded := &Recursion{dedBase: dedBase{cfg: sio.cfg}}
+	sio.rdBase(&ded.dedBase)
+	up := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read recursion")
+	if up >= uint64(len(sio.objs)) {
+		panic(eloc.Errorf("recursion %d levels up in %d objects", up, len(sio.objs)))
+	}
+	ded.Target = sio.objs[len(sio.objs)-1-int(up)]
+	ded.Path = sio.rdString()
+	return ded
+}
+
 func (sio *StateIO) wrDedAny(ded *Any) {
 	sio.wrBase(tidAny, &ded.dedBase)
+	ts := slices.Sorted(maps.Keys(ded.Types))
+	sio.buf = binary.AppendUvarint(sio.buf, uint64(len(ts)))
+	for _, t := range ts {
+		sio.buf = binary.AppendUvarint(sio.buf, uint64(t))
+		sio.buf = binary.AppendUvarint(sio.buf, uint64(ded.Types[t]))
+	}
 	must.RetCtx(sio.wr.Write(sio.buf)).Msg("any")
 }
 
 func (sio *StateIO) rdDedAny() *Any {
 	ded := &Any{dedBase: dedBase{cfg: sio.cfg}}
 	sio.rdBase(&ded.dedBase)
+	tno := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read any types")
+	if tno >= uint64(jsonInvalid) {
+		panic(eloc.Errorf("any with %d types", tno))
+	}
+	ded.Types = make(map[JsonType]int, tno)
+	for range tno {
+		t := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read any type")
+		if t == 0 || t >= uint64(jsonInvalid) {
+			panic(eloc.Errorf("invalid JSON type %d", t))
+		}
+		n := must.RetCtx(binary.ReadUvarint(&sio.rd)).Msg("read any %s count", JsonType(t))
+		ded.Types[JsonType(t)] = int(n)
+	}
 	return ded
 }
 
@@ -402,9 +666,10 @@ func (rc *restCountReader) ReadString(delim byte) (s string, err error) {
 }
 
 const (
-	statMinStrLen = 1
-	statMinMbrSz  = 5
-	statMinVarSz  = 3
+	statMinStrLen  = 1
+	statMinMbrSz   = 8
+	statMinVarSz   = 3
+	statMinShapeSz = 2
 )
 
 func (rc *restCountReader) checkU(s uint64, f string, a ...any) {
@@ -21,6 +21,7 @@ package jsum
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"math"
 	"strings"
 	"testing"
@@ -45,6 +46,18 @@ func TestStateIO_rdString(t *testing.T) {
 	testerr.Shall1(sio.ReadState(&buf, &testCfg, 0)).BeNil(t)
 }
 
+func TestStateIO_unknownTypeID(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		sio StateIO
+	)
+	fmt.Fprintf(&buf, "JSUM%d\n", StateVersion)
+	buf.WriteByte(tidRecursion + 1)
+	if _, err := sio.ReadState(&buf, &testCfg, 0); err == nil {
+		t.Error("read unknown deducer type id without error")
+	}
+}
+
 func testDedEq(t *testing.T, l, r Deducer) bool {
 	var lb, rb strings.Builder
 	testerr.Shall(json.NewEncoder(&lb).Encode(l)).BeNil(t)
@@ -81,8 +94,11 @@ func TestTestIO_writeRead(t *testing.T) {
 	t.Run("Number", func(t *testing.T) {
 		testDedWriteRead(t, &Number{dedBase: testDedBase,
 			Min: -math.Pi, Max: math.E,
-			IsFloat: true,
-			HasFrac: true,
+			IsFloat:    true,
+			HasFrac:    true,
+			GCD:        25,
+			Decimals:   2,
+			StepValues: 2,
 		})
 	})
 	t.Run("String", func(t *testing.T) {
@@ -99,21 +115,60 @@ func TestTestIO_writeRead(t *testing.T) {
 		testDedWriteRead(t, &Object{dedBase: testDedBase,
 			Members: map[string]Member{
 				"name": {
-					Occurence: 111,
-					Ded:       newString(&testCfg, 3, 1),
+					Occurence:  111,
+					Duplicates: 2,
+					Nulls:      1,
+					Empties:    17,
+					Ded:        newString(&testCfg, 3, 1),
 				},
 				"id": {
 					Occurence: 222,
 					Ded:       &Number{dedBase: testDedBase, Min: -100, Max: 100},
 				},
 			},
+			Shapes: map[string]int{
+				"id":                     3,
+				"id" + shapeSep + "name": 108,
+			},
+			ShapeOverflow: 7,
+			Orders: map[string]int{
+				"id" + shapeSep + "name": 100,
+				"name" + shapeSep + "id": 8,
+			},
+			OrderOverflow: 3,
+			Duplicates:    2,
 		})
 	})
 	t.Run("Array", func(t *testing.T) {
 		testDedWriteRead(t, &Array{dedBase: testDedBase,
-			MinLen: 1,
-			MaxLen: 1024,
+			MinLen:   1,
+			MaxLen:   1024,
+			Elem:     newString(&testCfg, 3, 1),
+			Elems:    4711,
+			Empty:    0,
+			LenHist:  []int{0, 3, 0, 7, 0, 0, 0, 0, 0, 0, 0, 1},
+			Scalars:  9,
+			UniqueNo: 8,
+			AscNo:    7,
+			DescNo:   1,
+		})
+		testDedWriteRead(t, &Array{dedBase: testDedBase,
+			MinLen: 2,
+			MaxLen: 2,
 			Elem:   newString(&testCfg, 3, 1),
+			Tuple: []Deducer{
+				newString(&testCfg, 3, 1),
+				&Number{dedBase: testDedBase, Min: -100, Max: 100},
+			},
+		})
+	})
+	t.Run("Map", func(t *testing.T) {
+		testDedWriteRead(t, &Map{dedBase: testDedBase,
+			Entries: 4711,
+			MinLen:  36,
+			MaxLen:  36,
+			Pattern: KeyUUID | KeyHex,
+			Value:   newString(&testCfg, 3, 1),
 		})
 	})
 	t.Run("Union", func(t *testing.T) {
@@ -124,8 +179,52 @@ func TestTestIO_writeRead(t *testing.T) {
 			},
 		})
 	})
+	t.Run("Tagged", func(t *testing.T) {
+		testDedWriteRead(t, &Tagged{dedBase: testDedBase,
+			Tag: "type",
+			Variants: []TagVariant{
+				{Values: []string{"a", "b"}, Ded: newString(&testCfg, 3, 1)},
+				{Values: []string{"c"}, Ded: &Any{dedBase: testDedBase}},
+			},
+		})
+	})
+	t.Run("Grouped", func(t *testing.T) {
+		testDedWriteRead(t, &Grouped{dedBase: testDedBase,
+			Path: "$.type",
+			Groups: map[string]Deducer{
+				`"a"`:        newString(&testCfg, 3, 1),
+				GroupMissing: &Any{dedBase: testDedBase},
+			},
+		})
+	})
+	t.Run("Recursion", func(t *testing.T) {
+		obj := &Object{dedBase: testDedBase, Members: make(map[string]Member)}
+		obj.Members["next"] = Member{
+			Occurence: 3,
+			Ded: &Recursion{
+				dedBase: testDedBase,
+				Path:    "$",
+				Target:  obj,
+			},
+		}
+		var (
+			buf bytes.Buffer
+			sio StateIO
+		)
+		testerr.Shall(sio.WriteState(&buf, obj)).BeNil(t)
+		ede := testerr.Shall1(sio.ReadState(&buf, &testCfg, 0)).BeNil(t)
+		testDedEq(t, ede, obj)
+		eo := ede.(*Object)
+		if r := eo.Members["next"].Ded.(*Recursion); r.Target != eo {
+			t.Error("recursion target not resolved")
+		}
+	})
 	t.Run("Any", func(t *testing.T) {
 		testDedWriteRead(t, &Any{dedBase: testDedBase})
+		testDedWriteRead(t, &Any{
+			dedBase: testDedBase,
+			Types:   map[JsonType]int{JsonString: 3, JsonObject: 2},
+		})
 	})
 }
 
@@ -154,11 +253,28 @@ func TestStateIO_minSizes(t *testing.T) {
 		}
 		sio.wrString(testString)
 		buf.Reset()
-		sio.wrMbr(testString, Member{Ded: &Any{}})
+		sio.wrMbr(testString, Member{Ded: &Unknown{}})
 		if l := buf.Len(); l != statMinMbrSz {
 			t.Errorf("unexpected member size: %d", l)
 		}
 	})
+	t.Run("statMinShapeSz", func(t *testing.T) {
+		var buf bytes.Buffer
+		sio := StateIO{
+			wr:   &buf,
+			strs: make(map[string]int64),
+			sids: make(map[int64]string),
+		}
+		obj := &Object{Shapes: map[string]int{testString: 0}}
+		sio.wrDedObj(obj)
+		buf.Reset()
+		sio.wrDedObj(obj)
+		// tid, count, null, duplicates, member count, shape and order
+		// count and overflow are 1 byte
+		if l := buf.Len() - 9; l != statMinShapeSz {
+			t.Errorf("unexpected shape size: %d", l)
+		}
+	})
 	t.Run("statMinVarSz", func(t *testing.T) {
 		var buf bytes.Buffer
 		sio := StateIO{
@@ -168,7 +284,7 @@ func TestStateIO_minSizes(t *testing.T) {
 		}
 		sio.wrString(testString)
 		buf.Reset()
-		sio.wrDed(&Any{})
+		sio.wrDed(&Unknown{})
 		if l := buf.Len(); l != statMinVarSz {
 			t.Errorf("unexpected variant size: %d", l)
 		}
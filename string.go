@@ -19,7 +19,9 @@ along with this program.  If not, see <https://www.gnu.org/licenses/>.
 package jsum
 
 import (
+	"maps"
 	"math"
+	"slices"
 	"time"
 	"unicode/utf8"
 )
@@ -57,6 +59,7 @@ func (a *String) Example(v any, jt JsumType, _ float64) Deducer {
 	case JsonNull:
 		a.Count++
 		a.Null++
+		return a
 	case JsonString:
 		a.Count++
 		switch jt.v {
@@ -93,6 +96,7 @@ func stringFormat(s string) Format {
 
 func (s *String) Hash(dh DedupHash) uint64 {
 	hash := s.dedBase.startHash(JsonString)
+	hash.WriteByte(byte(s.Format))
 	if s.cfg.Dedup.String&DedupStringEmpty != 0 {
 		if s.Stats[""] > 0 {
 			hash.WriteByte(1)
@@ -101,7 +105,7 @@ func (s *String) Hash(dh DedupHash) uint64 {
 		}
 	}
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], s)
+	dh.add(res, s)
 	return res
 }
 
@@ -110,18 +114,33 @@ func (s *String) Equal(d Deducer) bool {
 	if !ok {
 		return false
 	}
-	if !s.dedBase.Equal(&b.dedBase) {
+	if !s.dedBase.Equal(&b.dedBase) || s.Format != b.Format {
 		return false
 	}
-	if se, de := s.Stats[""], b.Stats[""]; (se > 0) != (de > 0) {
+	if s.cfg.Dedup.String&DedupStringEmpty != 0 &&
+		(s.Stats[""] > 0) != (b.Stats[""] > 0) {
 		return false
 	}
 	return true
 }
 
-func (a *String) JSONSchema() any {
+func (a *String) JSONSchema(opts *SchemaOptions) any {
 	scm := jscmString{
-		jscmType: jscmType{Type: "string"},
+		jscmType: schemaType("string", a.Null > 0),
+	}
+	switch opts.Strictness {
+	case SchemaLoose:
+		return scm
+	case SchemaStrict:
+		if a.isEnum() {
+			return nullable(jscmEnum{
+				jscmType: jscmType{Type: "string"},
+				Enum:     slices.Sorted(maps.Keys(a.Stats)),
+			}, a.Null > 0)
+		}
+		if a.Format == 0 {
+			scm.Pattern, scm.Format = a.pattern()
+		}
 	}
 	switch a.Format {
 	case 0:
@@ -138,10 +157,40 @@ func (a *String) JSONSchema() any {
 	case DateTimeFormat:
 		scm.Format = "date-time"
 	}
-	if a.Null > 0 {
-		return []any{"null", scm}
-	}
 	return scm
 }
 
+// strictEnumMax is the maximum number of distinct values of a string that is
+// emitted as enum by SchemaStrict.
+const strictEnumMax = 16
+
+// isEnum reports whether the values of a look like a fixed set, i.e. there
+// are few distinct values that occured repeatedly.
+func (a *String) isEnum() bool {
+	n := len(a.Stats)
+	return n > 0 && n <= strictEnumMax && 2*n <= a.Count-a.Null
+}
+
+// pattern returns a regular expression and a format that all values of a
+// match, if any.
+func (a *String) pattern() (pattern, format string) {
+	if len(a.Stats) == 0 {
+		return "", ""
+	}
+	p, date := keyAllPatterns, true
+	for s := range a.Stats {
+		p &= keyPattern(s)
+		date = date && len(s) == 10
+	}
+	switch p.Best() {
+	case KeyUUID:
+		format = "uuid"
+	case KeyDate:
+		if date {
+			format = "date"
+		}
+	}
+	return p.Regexp(), format
+}
+
 func (s *String) super() *dedBase { return &s.dedBase }
@@ -34,6 +34,9 @@ import (
 type SummaryConfig struct {
 	TreeStyle *tetrta.TreeStyle
 	StringMax int
+	// ShapeMax is the maximum number of object shapes to print. Zero disables
+	// the shape and member co-occurence section of objects.
+	ShapeMax int
 }
 
 type Summary struct {
@@ -61,6 +64,9 @@ func (s *Summary) printIndet(scm Deducer, last bool) (err error) {
 	} else {
 		io.WriteString(s.w, s.tree.Next(nil))
 	}
+	if n := TypeName(scm); n != "" {
+		fmt.Fprintf(s.w, "%s: ", n)
+	}
 	switch ded := scm.(type) {
 	case *String:
 		err = s.str(ded)
@@ -72,8 +78,16 @@ func (s *Summary) printIndet(scm Deducer, last bool) (err error) {
 		err = s.bool(ded)
 	case *Array:
 		err = s.array(ded)
+	case *Map:
+		err = s.mapDed(ded)
+	case *Tagged:
+		err = s.tagged(ded)
+	case *Grouped:
+		err = s.grouped(ded)
 	case *Union:
 		err = s.union(ded)
+	case *Recursion:
+		fmt.Fprintln(s.w, RecursionLabel(ded))
 	case *Any:
 		fmt.Fprintln(s.w, AnyLabel(ded))
 	case *Unknown:
@@ -97,7 +111,32 @@ func numsLabel(b *dedBase) string {
 	return fmt.Sprintf("[%d×]", b.Count)
 }
 
-func AnyLabel(ded *Any) string { return "Any " + numsLabel(&ded.dedBase) }
+func AnyLabel(ded *Any) string {
+	ts := ded.TypeList()
+	if len(ts) == 0 {
+		return "Any " + numsLabel(&ded.dedBase)
+	}
+	var total int
+	for _, n := range ded.Types {
+		total += n
+	}
+	var sb strings.Builder
+	sb.WriteString("Any (")
+	for i, t := range ts {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		p := math.Round(100 * float64(ded.Types[t]) / float64(total))
+		fmt.Fprintf(&sb, "%s %.0f%%", t, p)
+	}
+	sb.WriteString(") ")
+	sb.WriteString(numsLabel(&ded.dedBase))
+	return sb.String()
+}
+
+func RecursionLabel(ded *Recursion) string {
+	return "↻ " + ded.Path + " " + numsLabel(&ded.dedBase)
+}
 
 func UnknownLabel(ded *Unknown) string { return "??? " + numsLabel(&ded.dedBase) }
 
@@ -237,6 +276,10 @@ func NumberLabel(ded *Number) string {
 			sum = fmt.Sprintf("Integer %d–%d ", mi, ma)
 		}
 	}
+	if ded.stepRelevant() {
+		step, _ := ded.Step()
+		sum += fmt.Sprintf("step:%s ", strconv.FormatFloat(step, 'f', -1, 64))
+	}
 	return sum + numsLabel(&ded.dedBase)
 }
 
@@ -246,6 +289,13 @@ func (s *Summary) number(n *Number) error {
 }
 
 func ObjectLabel(ded *Object) string {
+	if ded.Duplicates > 0 {
+		return fmt.Sprintf("Object with %d members dup-keys:%d %s",
+			len(ded.Members),
+			ded.Duplicates,
+			numsLabel(&ded.dedBase),
+		)
+	}
 	return fmt.Sprintf("Object with %d members %s",
 		len(ded.Members),
 		numsLabel(&ded.dedBase),
@@ -259,25 +309,33 @@ func (s *Summary) object(o *Object) error {
 		nms = append(nms, a)
 	}
 	sort.Strings(nms)
+	withShapes := s.ShapeMax > 0 && len(o.Shapes) > 1
+	withOrders := s.ShapeMax > 0 && len(o.Orders) > 0
 	s.tree.Descend()
 	for i, a := range nms {
 		var pf string
-		if i == len(nms)-1 {
+		if i == len(nms)-1 && !withShapes && !withOrders {
 			pf = s.tree.Last(nil)
 		} else {
 			pf = s.tree.Next(nil)
 		}
 		m := o.Members[a]
 		fmt.Fprintf(s.w, "%s#%-2d \"%s\" ", pf, i+1, a)
-		if m.Occurence < o.Count {
+		if o.Absent(&m) > 0 {
 			fmt.Fprintf(s.w, "optional (%d/%d %.0f%%)",
 				m.Occurence,
-				o.Count,
-				100*float64(m.Occurence)/float64(o.Count),
+				o.Objects(),
+				100*float64(m.Occurence)/float64(o.Objects()),
 			)
 		} else {
 			fmt.Fprintf(s.w, "mandatory (%d×)", m.Occurence)
 		}
+		if l := MemberValuesLabel(o, &m); l != "" {
+			fmt.Fprint(s.w, " ", l)
+		}
+		if m.Duplicates > 0 {
+			fmt.Fprintf(s.w, " duplicate (%d×)", m.Duplicates)
+		}
 		fmt.Fprintln(s.w, ":")
 		s.tree.Descend()
 		if err := s.printIndet(m.Ded, true); err != nil {
@@ -285,25 +343,243 @@ func (s *Summary) object(o *Object) error {
 		}
 		s.tree.Ascend(1)
 	}
+	if withShapes {
+		s.shapes(o, !withOrders)
+	}
+	if withOrders {
+		s.orders(o)
+	}
 	s.tree.Ascend(1)
 	return nil
 }
 
+// MemberValuesLabel returns the breakdown of objects into those with a value,
+// null, an empty value or without member m. It returns the empty string if m
+// never was null or empty.
+func MemberValuesLabel(o *Object, m *Member) string {
+	if m.Nulls == 0 && m.Empties == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[value:%d", m.Values())
+	if m.Nulls > 0 {
+		fmt.Fprintf(&sb, " null:%d", m.Nulls)
+	}
+	if m.Empties > 0 {
+		fmt.Fprintf(&sb, " empty:%d", m.Empties)
+	}
+	if a := o.Absent(m); a > 0 {
+		fmt.Fprintf(&sb, " absent:%d", a)
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+func ShapesLabel(o *Object) string {
+	if o.ShapeOverflow > 0 {
+		return fmt.Sprintf("Shapes: %d distinct (%d× untracked)",
+			len(o.Shapes),
+			o.ShapeOverflow,
+		)
+	}
+	return fmt.Sprintf("Shapes: %d distinct", len(o.Shapes))
+}
+
+func ShapeLabel(sh Shape) string {
+	return fmt.Sprintf("%d× {%s}", sh.Count, strings.Join(sh.Members, ", "))
+}
+
+// CoOccurenceLabels returns one line for each group of members that always
+// occur together followed by one line for each pair of groups that never
+// occur together. If not all shapes were tracked, the lines only claim this
+// for the tracked shapes.
+func CoOccurenceLabels(co CoOccurence) (res []string) {
+	var partial string
+	if !co.Complete {
+		partial = " in tracked shapes"
+	}
+	for _, g := range co.Groups {
+		if len(g) > 1 {
+			res = append(res, "always together"+partial+": "+strings.Join(g, ", "))
+		}
+	}
+	for _, x := range co.Exclusive {
+		res = append(res, fmt.Sprintf("never together%s: {%s} / {%s}",
+			partial,
+			strings.Join(co.Groups[x[0]], ", "),
+			strings.Join(co.Groups[x[1]], ", "),
+		))
+	}
+	return res
+}
+
+func OrdersLabel(o *Object) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Member orders: %d distinct", len(o.Orders))
+	if o.OrderOverflow > 0 {
+		fmt.Fprintf(&sb, " (%d× untracked)", o.OrderOverflow)
+	}
+	if _, ok := o.MemberOrder(); ok {
+		sb.WriteString(" consistent")
+	} else {
+		sb.WriteString(" inconsistent")
+	}
+	return sb.String()
+}
+
+func OrderLabel(sh Shape) string {
+	return fmt.Sprintf("%d× [%s]", sh.Count, strings.Join(sh.Members, ", "))
+}
+
+func (s *Summary) shapes(o *Object, last bool) {
+	var lines []string
+	shapes := o.ShapeList()
+	for i, sh := range shapes {
+		if i == s.ShapeMax {
+			lines = append(lines, fmt.Sprintf("… %d more", len(shapes)-i))
+			break
+		}
+		lines = append(lines, ShapeLabel(sh))
+	}
+	lines = append(lines, CoOccurenceLabels(o.CoOccurence())...)
+	s.section(ShapesLabel(o), lines, last)
+}
+
+func (s *Summary) orders(o *Object) {
+	var lines []string
+	if order, ok := o.MemberOrder(); ok {
+		lines = append(lines, "canonical: ["+strings.Join(order, ", ")+"]")
+	}
+	orders := o.OrderList()
+	for i, sh := range orders {
+		if i == s.ShapeMax {
+			lines = append(lines, fmt.Sprintf("… %d more", len(orders)-i))
+			break
+		}
+		lines = append(lines, OrderLabel(sh))
+	}
+	s.section(OrdersLabel(o), lines, true)
+}
+
+func (s *Summary) section(label string, lines []string, last bool) {
+	if last {
+		fmt.Fprintf(s.w, "%s%s:\n", s.tree.Last(nil), label)
+	} else {
+		fmt.Fprintf(s.w, "%s%s:\n", s.tree.Next(nil), label)
+	}
+	s.tree.Descend()
+	for i, l := range lines {
+		if i == len(lines)-1 {
+			fmt.Fprintf(s.w, "%s%s\n", s.tree.Last(nil), l)
+		} else {
+			fmt.Fprintf(s.w, "%s%s\n", s.tree.Next(nil), l)
+		}
+	}
+	s.tree.Ascend(1)
+}
+
 func ArrayLabel(ded *Array) string {
+	if ded.IsTuple() {
+		return fmt.Sprintf("Tuple of %d %s", len(ded.Tuple), numsLabel(&ded.dedBase))
+	}
 	var lens string
 	if ded.MinLen == ded.MaxLen {
 		lens = strconv.Itoa(ded.MinLen)
 	} else {
-		lens = fmt.Sprintf("%d..%d", ded.MinLen, ded.MaxLen)
+		lens = fmt.Sprintf("%d..%d mean:%.1f", ded.MinLen, ded.MaxLen, ded.MeanLen())
+	}
+	if ded.Empty > 0 {
+		lens += fmt.Sprintf(" empty:%d", ded.Empty)
+	}
+	return fmt.Sprintf("Array of %s elems:%d%s %s",
+		lens, ded.Elems, SortLabel(ded), numsLabel(&ded.dedBase))
+}
+
+// SortLabel returns the uniqueness and sort order that all arrays with at
+// least 2 elements had or the empty string.
+func SortLabel(ded *Array) (res string) {
+	if ded.AlwaysUnique() {
+		res += " unique"
+	}
+	switch {
+	case ded.AlwaysAscending() && ded.AlwaysDescending():
+		res += " constant"
+	case ded.AlwaysAscending():
+		res += " sorted↑"
+	case ded.AlwaysDescending():
+		res += " sorted↓"
+	}
+	return res
+}
+
+// LenHistLabel returns the array length histogram as text or the empty
+// string if all arrays have the same length.
+func LenHistLabel(ded *Array) string {
+	if ded.MinLen == ded.MaxLen {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("lengths")
+	for b, n := range ded.LenHist {
+		if n == 0 {
+			continue
+		}
+		switch lmin, lmax := LenBucketRange(b); {
+		case lmin == lmax:
+			fmt.Fprintf(&sb, " %d:%d", lmin, n)
+		default:
+			fmt.Fprintf(&sb, " %d-%d:%d", lmin, lmax, n)
+		}
 	}
-	return fmt.Sprintf("Array of %s %s", lens, numsLabel(&ded.dedBase))
+	return sb.String()
 }
 
 func (s *Summary) array(a *Array) error {
 	fmt.Fprintf(s.w, "%s:\n", ArrayLabel(a))
 	s.tree.Descend()
 	defer s.tree.Ascend(1)
-	return s.printIndet(a.Elem, true)
+	if !a.IsTuple() {
+		if h := LenHistLabel(a); h != "" {
+			fmt.Fprintf(s.w, "%s%s\n", s.tree.Next(nil), h)
+		}
+		return s.printIndet(a.Elem, true)
+	}
+	for i, t := range a.Tuple {
+		if i == len(a.Tuple)-1 {
+			fmt.Fprintf(s.w, "%s[%d]:\n", s.tree.Last(nil), i)
+		} else {
+			fmt.Fprintf(s.w, "%s[%d]:\n", s.tree.Next(nil), i)
+		}
+		s.tree.Descend()
+		if err := s.printIndet(t, true); err != nil {
+			return err
+		}
+		s.tree.Ascend(1)
+	}
+	return nil
+}
+
+func MapLabel(ded *Map) string {
+	var lens string
+	if ded.MinLen == ded.MaxLen {
+		lens = strconv.Itoa(ded.MinLen)
+	} else {
+		lens = fmt.Sprintf("%d..%d", ded.MinLen, ded.MaxLen)
+	}
+	return fmt.Sprintf("Map with %d entries (%.1f/object) keys:%s len:%s %s",
+		ded.Entries,
+		ded.MeanSize(),
+		ded.Pattern,
+		lens,
+		numsLabel(&ded.dedBase),
+	)
+}
+
+func (s *Summary) mapDed(m *Map) error {
+	fmt.Fprintf(s.w, "%s:\n", MapLabel(m))
+	s.tree.Descend()
+	defer s.tree.Ascend(1)
+	return s.printIndet(m.Value, true)
 }
 
 func UnionLabel(u *Union) string {
@@ -313,6 +589,79 @@ func UnionLabel(u *Union) string {
 	)
 }
 
+func TaggedLabel(t *Tagged) string {
+	return fmt.Sprintf("Tagged union on \"%s\" of %d types %s",
+		t.Tag,
+		len(t.Variants),
+		numsLabel(&t.dedBase),
+	)
+}
+
+func TagVariantLabel(t *Tagged, v *TagVariant) string {
+	vals := make([]string, len(v.Values))
+	for i, val := range v.Values {
+		vals[i] = strconv.Quote(val)
+	}
+	return fmt.Sprintf("\"%s\" = %s", t.Tag, strings.Join(vals, " | "))
+}
+
+func (s *Summary) tagged(t *Tagged) error {
+	fmt.Fprintf(s.w, "%s:\n", TaggedLabel(t))
+	s.tree.Descend()
+	for i := range t.Variants {
+		v := &t.Variants[i]
+		if i == len(t.Variants)-1 {
+			io.WriteString(s.w, s.tree.Last(nil))
+		} else {
+			io.WriteString(s.w, s.tree.Next(nil))
+		}
+		fmt.Fprintf(s.w, "%s:\n", TagVariantLabel(t, v))
+		s.tree.Descend()
+		if err := s.printIndet(v.Ded, true); err != nil {
+			return err
+		}
+		s.tree.Ascend(1)
+	}
+	s.tree.Ascend(1)
+	return nil
+}
+
+func GroupedLabel(g *Grouped) string {
+	return fmt.Sprintf("Grouped by %s into %d groups %s",
+		g.Path,
+		len(g.Groups),
+		numsLabel(&g.dedBase),
+	)
+}
+
+func GroupLabel(g *Grouped, key string) string {
+	if key == GroupMissing {
+		return g.Path + " missing"
+	}
+	return g.Path + " = " + key
+}
+
+func (s *Summary) grouped(g *Grouped) error {
+	fmt.Fprintf(s.w, "%s:\n", GroupedLabel(g))
+	keys := g.GroupKeys()
+	s.tree.Descend()
+	for i, k := range keys {
+		if i == len(keys)-1 {
+			io.WriteString(s.w, s.tree.Last(nil))
+		} else {
+			io.WriteString(s.w, s.tree.Next(nil))
+		}
+		fmt.Fprintf(s.w, "%s:\n", GroupLabel(g, k))
+		s.tree.Descend()
+		if err := s.printIndet(g.Groups[k], true); err != nil {
+			return err
+		}
+		s.tree.Ascend(1)
+	}
+	s.tree.Ascend(1)
+	return nil
+}
+
 func (s *Summary) union(u *Union) error {
 	fmt.Fprintf(s.w, "%s:\n", UnionLabel(u))
 	s.tree.Descend()
@@ -325,6 +674,41 @@ func (s *Summary) union(u *Union) error {
 	return nil
 }
 
+// PrintNaming prints a report section with the naming issues of all objects
+// in d.
+func (s *Summary) PrintNaming(d Deducer) error {
+	issues := NamingIssues(d)
+	if _, err := fmt.Fprintf(s.w, "\nFound %d objects with naming issues\n", len(issues)); err != nil {
+		return err
+	}
+	for i := range issues {
+		iss := &issues[i]
+		s.section(iss.Path, NamingLabels(&iss.Naming), i == len(issues)-1)
+	}
+	return nil
+}
+
+// PrintSimilar prints the clusters of similar objects found by SimilarTypes.
+func (s *Summary) PrintSimilar(d Deducer, minSim float64) error {
+	cls := SimilarTypes(d, minSim)
+	if _, err := fmt.Fprintf(s.w, "\nFound %d clusters of similar objects\n", len(cls)); err != nil {
+		return err
+	}
+	for i := range cls {
+		cl := &cls[i]
+		lines := slices.Clone(cl.Paths)
+		for _, d := range cl.Diffs {
+			lines = append(lines, "≠ "+d.Label())
+		}
+		label := fmt.Sprintf("%d objects %.0f%% the same",
+			len(cl.Objects),
+			100*cl.Similarity,
+		)
+		s.section(label, lines, i == len(cls)-1)
+	}
+	return nil
+}
+
 func maxIntWidth(width int, i int) int {
 	if w := intWidth(i); w > width {
 		width = w
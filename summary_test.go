@@ -0,0 +1,45 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"git.fractalqb.de/fractalqb/testerr"
+)
+
+func TestSummary_singleOrder(t *testing.T) {
+	cfg := Config{Object: ObjectConfig{MaxShapes: 8, MaxOrders: 8}}
+	var d Deducer = NewUnknown(&cfg)
+	dec := json.NewDecoder(strings.NewReader(`{"b":1,"a":2} {"b":3,"a":4}`))
+	for range 2 {
+		v := testerr.Shall1(Decode(dec)).BeNil(t)
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	var sb strings.Builder
+	if err := NewSummary(&sb, &SummaryConfig{ShapeMax: 4}).Print(d); err != nil {
+		t.Fatal(err)
+	}
+	if s := sb.String(); !strings.Contains(s, "Member orders: 1 distinct") ||
+		!strings.Contains(s, "canonical: [b, a]") {
+		t.Errorf("single member order not shown in:\n%s", s)
+	}
+}
@@ -0,0 +1,337 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/binary"
+	"iter"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// Tagged is a discriminated union of objects. The variants are distinguished
+// by the string value of the Tag member.
+type Tagged struct {
+	dedBase
+	Tag      string       `json:"tag"`
+	Variants []TagVariant `json:"variants"`
+	index    map[string]int
+}
+
+// TagVariant is the variant of a Tagged union that is selected by one of the
+// tag Values.
+type TagVariant struct {
+	Values []string `json:"values"`
+	Ded    Deducer  `json:"type"`
+}
+
+// exampleTag returns the configured tag member of the object m and its value.
+// If m has no such member with a string value, tag is the empty string.
+func exampleTag(cfg *Config, m iter.Seq2[string, any]) (tag, value string) {
+	if cfg == nil || len(cfg.Union.Tags) == 0 {
+		return "", ""
+	}
+	for n, v := range m {
+		if s, ok := v.(string); ok && slices.Contains(cfg.Union.Tags, n) {
+			return n, s
+		}
+	}
+	return "", ""
+}
+
+func objTag(v any, tag string) (string, bool) {
+	tv, _ := objMember(v, tag)
+	s, ok := tv.(string)
+	return s, ok
+}
+
+func newTagged(cfg *Config, tag string, count, nulln int) *Tagged {
+	return &Tagged{
+		dedBase: dedBase{cfg: cfg, Count: count, Null: nulln},
+		Tag:     tag,
+	}
+}
+
+func (*Tagged) JsonType() JsonType { return JsonObject }
+
+func (t *Tagged) Accepts(v any, jt JsumType) float64 {
+	if jt.t == JsonObject {
+		if tv, ok := objTag(v, t.Tag); ok {
+			if t.variant(tv) >= 0 {
+				return 1
+			}
+			return 0.5
+		}
+	}
+	return 0
+}
+
+func (t *Tagged) Example(v any, jt JsumType, _ float64) Deducer {
+	if jt.t == JsonNull {
+		t.Count++
+		t.Null++
+		return t
+	}
+	if tv, ok := objTag(v, t.Tag); ok && jt.t == JsonObject {
+		t.Count++
+		if i := t.variant(tv); i >= 0 {
+			tvar := &t.Variants[i]
+			tvar.Ded = tvar.Ded.Example(v, jt, UnknownAccept)
+		} else {
+			t.index[tv] = len(t.Variants)
+			t.Variants = append(t.Variants, TagVariant{
+				Values: []string{tv},
+				Ded:    newObjJson(t.cfg, 0, 0, v, jt),
+			})
+		}
+		return t
+	}
+	u := newUnion(t)
+	return u.Example(v, jt, UnknownAccept)
+}
+
+func (t *Tagged) variant(value string) int {
+	if t.index == nil {
+		t.index = make(map[string]int)
+		for i, v := range t.Variants {
+			for _, val := range v.Values {
+				t.index[val] = i
+			}
+		}
+	}
+	if i, ok := t.index[value]; ok {
+		return i
+	}
+	return -1
+}
+
+func (t *Tagged) mergeTagged(b *Tagged) *Tagged {
+	t.Count += b.Count
+	t.Null += b.Null
+	for _, bv := range b.Variants {
+		i := -1
+		for _, val := range bv.Values {
+			if i = t.variant(val); i >= 0 {
+				break
+			}
+		}
+		if i < 0 {
+			t.Variants = append(t.Variants, bv)
+		} else {
+			tv := &t.Variants[i]
+			tv.Ded = merge(tv.Ded, bv.Ded)
+			for _, val := range bv.Values {
+				if !slices.Contains(tv.Values, val) {
+					tv.Values = append(tv.Values, val)
+				}
+			}
+		}
+		t.index = nil
+	}
+	return t
+}
+
+func (t *Tagged) Hash(dh DedupHash) uint64 {
+	dhs := make([]uint64, 0, len(t.Variants))
+	for _, v := range t.Variants {
+		dhs = append(dhs, v.Ded.Hash(dh))
+	}
+	sort.Slice(dhs, func(i, j int) bool { return dhs[i] < dhs[j] })
+	hash := t.dedBase.startHash(JsonObject)
+	hash.WriteByte(3)
+	hash.WriteString(t.Tag)
+	for _, h := range dhs {
+		binary.Write(hash, hashEndian, h)
+	}
+	res := hash.Sum64()
+	dh.add(res, t)
+	return res
+}
+
+func (t *Tagged) Equal(d Deducer) bool {
+	b, ok := d.(*Tagged)
+	if !ok {
+		return false
+	}
+	if !t.dedBase.Equal(&b.dedBase) ||
+		t.Tag != b.Tag ||
+		len(t.Variants) != len(b.Variants) {
+		return false
+	}
+	for _, tv := range t.Variants {
+		i := slices.IndexFunc(b.Variants, func(bv TagVariant) bool {
+			return sameValues(tv.Values, bv.Values)
+		})
+		if i < 0 || !tv.Ded.Equal(b.Variants[i].Ded) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Tagged) JSONSchema(opts *SchemaOptions) any {
+	scm := jscmOneOf{
+		OneOf:         make([]any, len(t.Variants)),
+		Discriminator: &jscmDiscriminator{PropertyName: t.Tag},
+	}
+	for i, v := range t.Variants {
+		vs := v.Ded.JSONSchema(opts)
+		if o, ok := vs.(jscmObj); ok {
+			if len(v.Values) == 1 {
+				o.Props[t.Tag] = jscmConst{Const: v.Values[0]}
+			} else {
+				o.Props[t.Tag] = jscmEnum{
+					jscmType: jscmType{Type: "string"},
+					Enum:     v.Values,
+				}
+			}
+			vs = o
+		}
+		scm.OneOf[i] = vs
+	}
+	return nullable(scm, t.Null > 0)
+}
+
+func (t *Tagged) super() *dedBase { return &t.dedBase }
+
+// Discriminate walks the deducer tree d and replaces unions of objects that
+// are cleanly split by the values of a common string member with Tagged
+// unions. A member qualifies as tag if it is a mandatory non-null string in
+// all object variants and no tag value occurs in more than one variant.
+// Members named in UnionConfig.Tags are preferred. Discriminate returns the
+// resulting deducer tree.
+func Discriminate(d Deducer) Deducer {
+	switch d := d.(type) {
+	case *Object:
+		for n, m := range d.Members {
+			m.Ded = Discriminate(m.Ded)
+			d.Members[n] = m
+		}
+	case *Array:
+		d.Elem = Discriminate(d.Elem)
+		for i, t := range d.Tuple {
+			d.Tuple[i] = Discriminate(t)
+		}
+	case *Map:
+		d.Value = Discriminate(d.Value)
+	case *Tagged:
+		for i := range d.Variants {
+			d.Variants[i].Ded = Discriminate(d.Variants[i].Ded)
+		}
+	case *Grouped:
+		for k, g := range d.Groups {
+			d.Groups[k] = Discriminate(g)
+		}
+	case *Union:
+		for i, v := range d.Variants {
+			d.Variants[i] = Discriminate(v)
+		}
+		return d.discriminate()
+	}
+	return d
+}
+
+func (u *Union) discriminate() Deducer {
+	var objs []*Object
+	for _, v := range u.Variants {
+		if o, ok := v.(*Object); ok {
+			objs = append(objs, o)
+		}
+	}
+	if len(objs) < 2 {
+		return u
+	}
+	tag := objsTag(u.cfg, objs)
+	if tag == "" {
+		return u
+	}
+	t := newTagged(u.cfg, tag, 0, 0)
+	others := u.Variants[:0]
+	for _, v := range u.Variants {
+		o, ok := v.(*Object)
+		if !ok {
+			others = append(others, v)
+			continue
+		}
+		str := o.Members[tag].Ded.(*String)
+		vals := slices.Sorted(maps.Keys(str.Stats))
+		t.Variants = append(t.Variants, TagVariant{Values: vals, Ded: o})
+		t.Count += o.Count
+		t.Null += o.Null
+	}
+	if len(others) == 0 {
+		t.Count, t.Null = u.Count, u.Null
+		return t
+	}
+	u.Variants = append(others, t)
+	return u
+}
+
+func objsTag(cfg *Config, objs []*Object) string {
+	var cands []string
+NEXT_MEMBER:
+	for n, m := range objs[0].Members {
+		seen := make(map[string]bool)
+		for i, o := range objs {
+			if i > 0 {
+				if m = o.Members[n]; m.Ded == nil {
+					continue NEXT_MEMBER
+				}
+			}
+			str, ok := m.Ded.(*String)
+			if !ok || str.Null > 0 || o.Absent(&m) > 0 {
+				continue NEXT_MEMBER
+			}
+			for s := range str.Stats {
+				if seen[s] {
+					continue NEXT_MEMBER
+				}
+			}
+			for s := range str.Stats {
+				seen[s] = true
+			}
+		}
+		cands = append(cands, n)
+	}
+	if len(cands) == 0 {
+		return ""
+	}
+	if cfg != nil {
+		for _, tag := range cfg.Union.Tags {
+			if slices.Contains(cands, tag) {
+				return tag
+			}
+		}
+	}
+	slices.Sort(cands)
+	return cands[0]
+}
@@ -0,0 +1,131 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTagged_Example(t *testing.T) {
+	cfg := Config{Union: UnionConfig{Tags: []string{"kind", "type"}}}
+	var d Deducer = NewUnknown(&cfg)
+	for _, v := range []map[string]any{
+		{"type": "click", "x": 1.0},
+		{"type": "view", "page": "p"},
+		{"type": "click", "x": 2.0},
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	tg, ok := d.(*Tagged)
+	if !ok {
+		t.Fatalf("deduced not tagged union but %T", d)
+	}
+	if tg.Tag != "type" || tg.Count != 3 || len(tg.Variants) != 2 {
+		t.Fatalf("unexpected tagged union %+v", tg)
+	}
+	if o := tg.Variants[0].Ded.(*Object); o.Count != 2 || len(o.Members) != 2 {
+		t.Errorf("unexpected click variant %+v", o)
+	}
+}
+
+func TestDiscriminate(t *testing.T) {
+	cfg := Config{Union: UnionConfig{
+		MergeRejectMax: 0.5,
+		Combine:        []TypeSet{AllTypes},
+	}}
+	var d Deducer = NewUnknown(&cfg)
+	for _, v := range []any{
+		map[string]any{"event": "a", "x": 1.0, "y": 1.0},
+		map[string]any{"event": "b", "s": "foo", "t": "bar"},
+		map[string]any{"event": "c", "x": 1.0, "y": 1.0},
+		"no object",
+	} {
+		d = d.Example(v, JsonTypeOf(v), UnknownAccept)
+	}
+	u, ok := d.(*Union)
+	if !ok {
+		t.Fatalf("deduced not union but %T", d)
+	}
+	if l := len(u.Variants); l != 3 {
+		t.Fatalf("unexpected number of variants: %d", l)
+	}
+	d = Discriminate(d)
+	if u, ok = d.(*Union); !ok || len(u.Variants) != 2 {
+		t.Fatalf("unexpected discriminated %T", d)
+	}
+	tg, ok := u.Variants[1].(*Tagged)
+	if !ok {
+		t.Fatalf("no tagged variant but %T", u.Variants[1])
+	}
+	if tg.Tag != "event" || tg.Count != 3 {
+		t.Errorf("unexpected tagged union on '%s' count %d", tg.Tag, tg.Count)
+	}
+	var vals [][]string
+	for _, v := range tg.Variants {
+		vals = append(vals, v.Values)
+	}
+	slices.SortFunc(vals, slices.Compare)
+	if !slices.EqualFunc(vals, [][]string{{"a", "c"}, {"b"}}, slices.Equal) {
+		t.Errorf("unexpected tag values %v", vals)
+	}
+}
+
+func TestDiscriminate_tuple(t *testing.T) {
+	cfg := Config{
+		Union: UnionConfig{
+			MergeRejectMax: 0.5,
+			Combine:        []TypeSet{AllTypes},
+		},
+		Object: ObjectConfig{MaxShapes: 8},
+		Array:  ArrayConfig{MaxTuple: 2},
+	}
+	d := Discriminate(deduceAll(&cfg,
+		[]any{1.0, map[string]any{"s": map[string]any{"kind": "circle", "r": 1.0}}},
+		[]any{2.0, map[string]any{"s": map[string]any{"kind": "rect", "w": 1.0, "h": 2.0}}},
+	))
+	a, ok := d.(*Array)
+	if !ok || !a.IsTuple() {
+		t.Fatalf("deduced no tuple but %T", d)
+	}
+	o := a.Tuple[1].(*Object)
+	if tg, ok := o.Members["s"].Ded.(*Tagged); !ok || tg.Tag != "kind" {
+		t.Errorf("tuple element not discriminated: %T", o.Members["s"].Ded)
+	}
+}
+
+func TestDiscriminate_singleObject(t *testing.T) {
+	cfg := Config{Object: ObjectConfig{MaxShapes: 8}}
+	var vs []any
+	for i := range 10 {
+		v := map[string]any{"id": float64(i), "color": "blue", "b": 1.0}
+		switch {
+		case i < 3:
+			v["color"] = "red"
+		case i >= 7:
+			delete(v, "b")
+			v["a"] = 1.0
+		}
+		vs = append(vs, v)
+	}
+	d := Discriminate(deduceAll(&cfg, vs...))
+	if _, ok := d.(*Object); !ok {
+		t.Errorf("single object split into %T", d)
+	}
+}
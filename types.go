@@ -0,0 +1,287 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TypeDef is a structured type that occurs more than once in a deduced
+// schema.
+type TypeDef struct {
+	Name string
+	// Ded is the first occurence of the type.
+	Ded Deducer
+	// Paths are the paths of all occurences in the deducer tree.
+	Paths []string
+	occs  []Deducer
+}
+
+// merged returns a copy of the type with the examples of all its
+// occurences, e.g. for the widest observed bounds.
+func (def *TypeDef) merged() Deducer {
+	res := clone(def.Ded)
+	for _, d := range def.occs {
+		if _, ok := d.(*Recursion); !ok && d != def.Ded {
+			res = mergeOcc(res, clone(d))
+		}
+	}
+	return res
+}
+
+// mergeOcc merges the occurence b of a type into the equal occurence a.
+// Unlike merge, which merges union variants by JSON type, each union variant
+// is merged with the equal variant of the other union. This is also done for
+// unions in members, elements and values. Afterwards b must not be used.
+func mergeOcc(a, b Deducer) Deducer {
+	switch a := a.(type) {
+	case *Union:
+		bu, ok := b.(*Union)
+		if !ok {
+			break
+		}
+		a.Count += bu.Count
+		a.Null += bu.Null
+		used := make([]bool, len(a.Variants))
+	NEXT_VARIANT:
+		for _, bv := range bu.Variants {
+			for i, av := range a.Variants {
+				if !used[i] && av.Equal(bv) {
+					a.Variants[i], used[i] = mergeOcc(av, bv), true
+					continue NEXT_VARIANT
+				}
+			}
+			a.Variants = append(a.Variants, bv)
+		}
+		return a
+	case *Object:
+		bo, ok := b.(*Object)
+		if !ok {
+			break
+		}
+		// Merge shared members first and leave empty deducers for mergeObj
+		for n, bm := range bo.Members {
+			if am, ok := a.Members[n]; ok {
+				am.Ded = mergeOcc(am.Ded, bm.Ded)
+				a.Members[n] = am
+				bm.Ded = NewUnknown(a.cfg)
+				bo.Members[n] = bm
+			}
+		}
+	case *Array:
+		if ba, ok := b.(*Array); ok {
+			a.Elem = mergeOcc(a.Elem, ba.Elem)
+			ba.Elem = NewUnknown(a.cfg)
+		}
+	case *Map:
+		if bm, ok := b.(*Map); ok {
+			a.Value = mergeOcc(a.Value, bm.Value)
+			bm.Value = NewUnknown(a.cfg)
+		}
+	}
+	return merge(a, b)
+}
+
+// NameTypes finds objects, maps and unions that occur more than once with
+// equal structure in d and objects that are the target of a Recursion. Each
+// of these reused types gets a name that is derived from the member names it
+// occurs with, e.g. "Address" for "$.billing.address" and
+// "$.shipping.address". The names are used by Schema and can be queried with
+// TypeName. The result is sorted by name.
+func NameTypes(d Deducer) []TypeDef {
+	walkTypes(d, "$", "root", func(d Deducer, _, _ string) {
+		b := d.super()
+		b.orig, b.copies, b.typeName = nil, nil, ""
+	})
+	d.Hash(make(DedupHash))
+	occs := make(map[Deducer]*typeOccs)
+	var order []Deducer
+	walkTypes(d, "$", "root", func(d Deducer, path, name string) {
+		if !reusable(d) {
+			return
+		}
+		key := d
+		if r, ok := d.(*Recursion); ok {
+			key = r.Target
+		} else if o := d.super().orig; o != nil {
+			key = o
+		}
+		occ := occs[key]
+		if occ == nil {
+			occ = &typeOccs{names: make(map[string]int)}
+			occs[key] = occ
+			order = append(order, key)
+		}
+		occ.paths = append(occ.paths, path)
+		occ.ds = append(occ.ds, d)
+		occ.names[typeName(name)]++
+	})
+	var res []TypeDef
+	used := make(map[string]bool)
+	for _, key := range order {
+		occ := occs[key]
+		if len(occ.paths) < 2 {
+			continue
+		}
+		name := occ.name()
+		if used[name] {
+			for i := 2; ; i++ {
+				if n := name + strconv.Itoa(i); !used[n] {
+					name = n
+					break
+				}
+			}
+		}
+		used[name] = true
+		for _, d := range occ.ds {
+			d.super().typeName = name
+		}
+		res = append(res, TypeDef{Name: name, Ded: key, Paths: occ.paths, occs: occ.ds})
+	}
+	slices.SortFunc(res, func(a, b TypeDef) int { return strings.Compare(a.Name, b.Name) })
+	return res
+}
+
+// TypeName returns the name that was assigned to d by NameTypes or the empty
+// string.
+func TypeName(d Deducer) string { return d.super().typeName }
+
+// schemaOf returns a reference for named types and the JSON schema of d
+// otherwise. Both are annotated if SchemaOptions.Annotate is set.
+func schemaOf(d Deducer, opts *SchemaOptions) any {
+	if n := TypeName(d); n != "" {
+		return annotate(d, jscmRef{Ref: "#/$defs/" + n}, opts)
+	}
+	return annotate(d, d.JSONSchema(opts), opts)
+}
+
+type typeOccs struct {
+	paths []string
+	ds    []Deducer
+	names map[string]int
+}
+
+// name returns the most frequent name of the occurences. Ties are broken by
+// the shortest and then the lexically first name.
+func (occ *typeOccs) name() string {
+	ns := slices.Collect(maps.Keys(occ.names))
+	slices.SortFunc(ns, func(a, b string) int {
+		if c := occ.names[b] - occ.names[a]; c != 0 {
+			return c
+		}
+		if c := cmp.Compare(len(a), len(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	return ns[0]
+}
+
+func reusable(d Deducer) bool {
+	switch d.(type) {
+	case *Object, *Map, *Tagged, *Union, *Recursion:
+		return true
+	}
+	return false
+}
+
+// typeNames returns the type names of all deducers in d that NameTypes may
+// change, including empty names.
+func typeNames(d Deducer) map[Deducer]string {
+	res := make(map[Deducer]string)
+	walkTypes(d, "$", "", func(d Deducer, _, _ string) {
+		res[d] = d.super().typeName
+	})
+	return res
+}
+
+// setTypeNames restores type names returned by typeNames.
+func setTypeNames(names map[Deducer]string) {
+	for d, n := range names {
+		d.super().typeName = n
+	}
+}
+
+// walkTypes calls f for all deducers in d with their path and the name of the
+// member they belong to. Variants of Tagged unions are skipped because their
+// schema is extended with the tag.
+func walkTypes(d Deducer, path, name string, f func(d Deducer, path, name string)) {
+	f(d, path, name)
+	switch d := d.(type) {
+	case *Object:
+		for _, n := range slices.Sorted(maps.Keys(d.Members)) {
+			walkTypes(d.Members[n].Ded, path+"."+n, n, f)
+		}
+	case *Array:
+		walkTypes(d.Elem, path+"[*]", name+" item", f)
+		for i, t := range d.Tuple {
+			walkTypes(t, fmt.Sprintf("%s[%d]", path, i), fmt.Sprintf("%s item %d", name, i), f)
+		}
+	case *Map:
+		walkTypes(d.Value, path+".*", name+" value", f)
+	case *Union:
+		for _, v := range d.Variants {
+			walkTypes(v, path, name, f)
+		}
+	case *Tagged:
+		for _, v := range d.Variants {
+			if o, ok := v.Ded.(*Object); ok {
+				for _, n := range slices.Sorted(maps.Keys(o.Members)) {
+					walkTypes(o.Members[n].Ded, path+"."+n, n, f)
+				}
+			} else {
+				walkTypes(v.Ded, path, name, f)
+			}
+		}
+	case *Grouped:
+		for _, k := range d.GroupKeys() {
+			walkTypes(d.Groups[k], path, name, f)
+		}
+	}
+}
+
+// typeName converts a member name to a type name in Pascal case.
+func typeName(name string) string {
+	var sb strings.Builder
+	up, lower := true, false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if up || (lower && unicode.IsUpper(r)) {
+				sb.WriteRune(unicode.ToUpper(r))
+			} else {
+				sb.WriteRune(r)
+			}
+			up, lower = false, unicode.IsLower(r)
+		default:
+			up, lower = true, false
+		}
+	}
+	res := sb.String()
+	if res == "" || !unicode.IsLetter([]rune(res)[0]) {
+		res = "Type" + res
+	}
+	return res
+}
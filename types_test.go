@@ -0,0 +1,147 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNameTypes(t *testing.T) {
+	addr := func(s string) map[string]any {
+		return map[string]any{"street": s, "zip": "1"}
+	}
+	v := map[string]any{
+		"billing_address":  addr("a"),
+		"shipping_address": addr("b"),
+		"pickup":           map[string]any{"address": addr("c")},
+		"id":               "x",
+	}
+	d := Deduce(&testCfg, v)
+	defs := NameTypes(d)
+	if len(defs) != 1 {
+		t.Fatalf("found %d reused types", len(defs))
+	}
+	if defs[0].Name != "Address" {
+		t.Errorf("unexpected type name '%s'", defs[0].Name)
+	}
+	if !slices.Equal(defs[0].Paths, []string{
+		"$.billing_address",
+		"$.pickup.address",
+		"$.shipping_address",
+	}) {
+		t.Errorf("unexpected paths %v", defs[0].Paths)
+	}
+	o := d.(*Object)
+	if n := TypeName(o.Members["shipping_address"].Ded); n != "Address" {
+		t.Errorf("unexpected name '%s' of shipping address", n)
+	}
+	if n := TypeName(o.Members["id"].Ded); n != "" {
+		t.Errorf("string got type name '%s'", n)
+	}
+
+	scm, err := Schema(d, SchemaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(scm)
+	js := string(data)
+	if !strings.Contains(js, `"$defs":{"Address":{`) {
+		t.Errorf("no Address definition in %s", js)
+	}
+	if c := strings.Count(js, `"$ref":"#/$defs/Address"`); c != 3 {
+		t.Errorf("%d references to Address in %s", c, js)
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	for _, test := range [][2]string{
+		{"address", "Address"},
+		{"billingAddress", "BillingAddress"},
+		{"shipping_address", "ShippingAddress"},
+		{"line-items item", "LineItemsItem"},
+		{"ID", "ID"},
+		{"2fa", "Type2fa"},
+		{"", "Type"},
+	} {
+		if n := typeName(test[0]); n != test[1] {
+			t.Errorf("type name of '%s' is '%s', want '%s'", test[0], n, test[1])
+		}
+	}
+}
+
+func TestSchema_keepsTypeNames(t *testing.T) {
+	addr := map[string]any{"street": "s", "zip": "1"}
+	d := Deduce(&testCfg, map[string]any{"home": addr, "work": addr})
+	home := d.(*Object).Members["home"].Ded
+	if _, err := Schema(d, SchemaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := TypeName(home); n != "" {
+		t.Errorf("schema left type name '%s'", n)
+	}
+	NameTypes(d)
+	if _, err := Schema(d, SchemaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := TypeName(home); n != "Home" {
+		t.Errorf("schema changed type name to '%s'", n)
+	}
+}
+
+func TestTypeDef_mergedUnion(t *testing.T) {
+	cfg := testCfg
+	cfg.Union = UnionConfig{MergeRejectMax: 0.5, Combine: []TypeSet{AllTypes}}
+	occ := func(n float64) Deducer {
+		return deduceAll(&cfg,
+			map[string]any{"name": "a", "id": "x"},
+			map[string]any{"size": n, "weight": n},
+		)
+	}
+	a, b := occ(1), occ(5)
+	def := TypeDef{Name: "Item", Ded: a, occs: []Deducer{a, b}}
+	u, ok := def.merged().(*Union)
+	if !ok {
+		t.Fatalf("merged to %T", def.merged())
+	}
+	if len(u.Variants) != 2 {
+		t.Fatalf("merged to %d variants", len(u.Variants))
+	}
+	if u.Count != 4 {
+		t.Errorf("merged count %d", u.Count)
+	}
+	for _, v := range u.Variants {
+		o := v.(*Object)
+		if len(o.Members) != 2 {
+			t.Errorf("variant with members %v", slices.Sorted(maps.Keys(o.Members)))
+		}
+		if m, ok := o.Members["size"]; ok {
+			nr := m.Ded.(*Number)
+			if nr.Min != 1 || nr.Max != 5 {
+				t.Errorf("size not in [1, 5]: [%v, %v]", nr.Min, nr.Max)
+			}
+		}
+	}
+	if len(a.(*Union).Variants[1].(*Object).Members) != 2 {
+		t.Error("merging changed the type")
+	}
+}
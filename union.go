@@ -20,7 +20,6 @@ package jsum
 
 import (
 	"encoding/binary"
-	"slices"
 	"sort"
 )
 
@@ -73,7 +72,9 @@ func (u *Union) Example(v any, jt JsumType, _ float64) Deducer {
 			return u
 		}
 	}
-	return newAny(u.cfg, u.Count, u.Null)
+	res := newAnyFrom(u)
+	res.Types[jt.JsonType()]++
+	return res
 }
 
 func (u *Union) Hash(dh DedupHash) uint64 {
@@ -87,7 +88,7 @@ func (u *Union) Hash(dh DedupHash) uint64 {
 		binary.Write(hash, hashEndian, h)
 	}
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], u)
+	dh.add(res, u)
 	return res
 }
 
@@ -96,24 +97,39 @@ func (u *Union) Equal(d Deducer) bool {
 	if !ok {
 		return false
 	}
-	res := u.dedBase.Equal(&b.dedBase) && len(u.Variants) == len(b.Variants)
-	if res {
-		for i := range b.Variants {
-			if res = u.Variants[i].Equal(b.Variants[i]); !res {
-				break
+	if !u.dedBase.Equal(&b.dedBase) {
+		return false
+	}
+	return equalVariants(u.Variants, b.Variants)
+}
+
+// equalVariants reports whether each of the deducers in a has an equal
+// deducer in b and vice versa, regardless of their order.
+func equalVariants(a, b []Deducer) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+NEXT_VARIANT:
+	for _, av := range a {
+		for i, bv := range b {
+			if !used[i] && av.Equal(bv) {
+				used[i] = true
+				continue NEXT_VARIANT
 			}
 		}
+		return false
 	}
-	return res
+	return true
 }
 
-func (u *Union) JSONSchema() any {
+func (u *Union) JSONSchema(opts *SchemaOptions) any {
 	scm := jscmAnyOf{AnyOf: make([]any, len(u.Variants))}
 	for i, v := range u.Variants {
-		scm.AnyOf[i] = v.JSONSchema()
+		scm.AnyOf[i] = schemaOf(v, opts)
 	}
-	if u.Null > 0 && !slices.Contains(scm.AnyOf, "null") {
-		scm.AnyOf = append(scm.AnyOf, "null")
+	if u.Null > 0 {
+		scm.AnyOf = append(scm.AnyOf, jscmType{Type: "null"})
 	}
 	return scm
 }
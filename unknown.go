@@ -46,16 +46,20 @@ func (a *Unknown) Example(v any, jt JsumType, _ float64) Deducer {
 		b := newBool(a.cfg, a.Count, a.Null)
 		return b.Example(v, jt, UnknownAccept)
 	case JsonObject:
-		switch jt.v {
-		case jsonObjStrAny:
-			return newObjJson(a.cfg, a.Count, a.Null, objStrAnySeq(v))
+		if m := objSeq(v, jt); m != nil {
+			if tag, _ := exampleTag(a.cfg, m); tag != "" {
+				t := newTagged(a.cfg, tag, a.Count, a.Null)
+				return t.Example(v, jt, UnknownAccept)
+			}
+			return newObjJson(a.cfg, a.Count, a.Null, v, jt)
 		}
+		return newAnyFrom(a).Example(v, jt, UnknownAccept)
 	case JsonArray:
-		switch v := v.(type) {
-		case []any:
+		if _, ok := arrElems(v, jt); ok {
 			ded := newArrJson(a.cfg, a.Count, a.Null)
 			return ded.Example(v, jt, UnknownAccept)
 		}
+		return newAnyFrom(a).Example(v, jt, UnknownAccept)
 	}
 	return newInvalid(fmt.Errorf("cannot deduce type from: %T", v))
 }
@@ -63,7 +67,7 @@ func (a *Unknown) Example(v any, jt JsumType, _ float64) Deducer {
 func (a *Unknown) Hash(dh DedupHash) uint64 {
 	hash := a.dedBase.startHash(JsonUnknown)
 	res := hash.Sum64()
-	dh[res] = addNotEqual(dh[res], a)
+	dh.add(res, a)
 	return res
 }
 
@@ -75,6 +79,12 @@ func (a *Unknown) Equal(d Deducer) bool {
 	return a.dedBase.Equal(&b.dedBase)
 }
 
-func (*Unknown) JSONSchema() any { return false }
+// JSONSchema allows anything if no value was seen and only null otherwise.
+func (a *Unknown) JSONSchema(*SchemaOptions) any {
+	if a.Null > 0 {
+		return jscmType{Type: "null"}
+	}
+	return struct{}{}
+}
 
 func (a *Unknown) super() *dedBase { return &a.dedBase }
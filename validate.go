@@ -0,0 +1,319 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ViolationKind classifies how a JSON value deviates from a deduced type.
+type ViolationKind int
+
+const (
+	// UnknownMember is a member that was never seen in the objects.
+	UnknownMember ViolationKind = iota + 1
+
+	// MissingMember is a member that all objects had.
+	MissingMember
+
+	// TypeMismatch is a value of a JSON type, a null or a string format that
+	// was not seen.
+	TypeMismatch
+
+	// OutOfRange is a number or array length outside the observed bounds.
+	OutOfRange
+
+	// UnseenValue is a value that was not seen where only a few distinct
+	// values were seen, e.g. tags, booleans or enum-like strings.
+	UnseenValue
+)
+
+var violationNames = []string{
+	UnknownMember: "unknown member",
+	MissingMember: "missing member",
+	TypeMismatch:  "type mismatch",
+	OutOfRange:    "out of range",
+	UnseenValue:   "unseen value",
+}
+
+func (k ViolationKind) String() string {
+	if k > 0 && int(k) < len(violationNames) {
+		return violationNames[k]
+	}
+	return fmt.Sprintf("ViolationKind(%d)", int(k))
+}
+
+// Violation is a deviation of the value at Path in the Record-th JSON
+// document from the deduced type.
+type Violation struct {
+	Record int
+	Path   string
+	Kind   ViolationKind
+	Msg    string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("record %d: %s: %s: %s", v.Record, v.Path, v.Kind, v.Msg)
+}
+
+// Validate checks the JSON value v of record number record against the
+// deduced type d and returns all violations.
+func Validate(d Deducer, record int, v any) []Violation {
+	vl := validation{record: record}
+	vl.check(d, "$", v)
+	return vl.res
+}
+
+type validation struct {
+	record int
+	res    []Violation
+}
+
+func (vl *validation) add(path string, kind ViolationKind, format string, args ...any) {
+	vl.res = append(vl.res, Violation{
+		Record: vl.record,
+		Path:   path,
+		Kind:   kind,
+		Msg:    fmt.Sprintf(format, args...),
+	})
+}
+
+func (vl *validation) check(d Deducer, path string, v any) {
+	jt := JsonTypeOf(v)
+	if !jt.Valid() {
+		vl.add(path, TypeMismatch, "no JSON value: %T", v)
+		return
+	}
+	if jt.t == JsonNull {
+		if !acceptsNull(d) {
+			vl.add(path, TypeMismatch, "null was not seen")
+		}
+		return
+	}
+	switch d := d.(type) {
+	case *Unknown:
+		if d.Count > 0 {
+			vl.add(path, TypeMismatch, "%s where only null was seen", jt.t)
+		}
+	case Invalid:
+	case *Any:
+		if d.Types[jt.t] == 0 {
+			vl.add(path, TypeMismatch, "%s was not seen, only %s", jt.t, d.TypeList())
+		}
+	case *Union:
+		vl.checkVariants(d, path, v, jt)
+	case *Recursion:
+		vl.check(d.Target, path, v)
+	case *Grouped:
+		if g, ok := d.Groups[d.GroupKey(v)]; ok {
+			vl.check(g, path, v)
+		} else {
+			vl.add(path, UnseenValue, "group %s was not seen", d.GroupKey(v))
+		}
+	case *String:
+		vl.checkString(d, path, v, jt)
+	case *Number:
+		vl.checkNumber(d, path, v, jt)
+	case *Boolean:
+		if jt.t != JsonBoolean {
+			vl.mismatch(path, d, jt)
+		} else if b := reflect.ValueOf(v).Bool(); b && d.TrueNo == 0 || !b && d.FalseNo == 0 {
+			vl.add(path, UnseenValue, "%t was not seen", b)
+		}
+	case *Array:
+		vl.checkArray(d, path, v, jt)
+	case *Object:
+		vl.checkObject(d, path, v, jt)
+	case *Map:
+		m := objSeq(v, jt)
+		if m == nil {
+			vl.mismatch(path, d, jt)
+			return
+		}
+		best := d.Pattern.Best()
+		for k, mv := range sortedMembers(m) {
+			if best != 0 && keyPattern(k)&best == 0 {
+				vl.add(path+"."+k, UnseenValue, "key does not match %s pattern", best)
+			}
+			vl.check(d.Value, path+"."+k, mv)
+		}
+	case *Tagged:
+		if objSeq(v, jt) == nil {
+			vl.mismatch(path, d, jt)
+			return
+		}
+		tv, ok := objTag(v, d.Tag)
+		switch i := d.variant(tv); {
+		case !ok:
+			vl.add(path+"."+d.Tag, MissingMember, "tag is missing or no string")
+		case i < 0:
+			vl.add(path+"."+d.Tag, UnseenValue, "tag value %q was not seen", tv)
+		default:
+			vl.check(d.Variants[i].Ded, path, v)
+		}
+	}
+}
+
+// checkVariants checks v against all variants of u with v's JSON type. Only
+// if v violates all of them, the violations of the closest variant, i.e. the
+// one with the fewest violations, are reported.
+func (vl *validation) checkVariants(u *Union, path string, v any, jt JsumType) {
+	var best []Violation
+	found := false
+	for _, vd := range u.Variants {
+		if vd.JsonType() != jt.t {
+			continue
+		}
+		sub := validation{record: vl.record}
+		sub.check(vd, path, v)
+		if !found || len(sub.res) < len(best) {
+			best, found = sub.res, true
+		}
+		if len(best) == 0 {
+			return
+		}
+	}
+	if !found {
+		vl.mismatch(path, u, jt)
+		return
+	}
+	vl.res = append(vl.res, best...)
+}
+
+// sortedMembers returns the members of m ordered by name so that violations
+// do not depend on the iteration order of Go maps. Duplicate members keep
+// their order.
+func sortedMembers(m iter.Seq2[string, any]) iter.Seq2[string, any] {
+	type member struct {
+		name string
+		v    any
+	}
+	var ms []member
+	for n, v := range m {
+		ms = append(ms, member{n, v})
+	}
+	slices.SortStableFunc(ms, func(a, b member) int { return strings.Compare(a.name, b.name) })
+	return func(yield func(string, any) bool) {
+		for _, m := range ms {
+			if !yield(m.name, m.v) {
+				return
+			}
+		}
+	}
+}
+
+func (vl *validation) mismatch(path string, d Deducer, jt JsumType) {
+	vl.add(path, TypeMismatch, "%s instead of %s", jt.t, d.JsonType())
+}
+
+// acceptsNull reports whether null was seen for d or a variant of d.
+func acceptsNull(d Deducer) bool {
+	switch d := d.(type) {
+	case *Unknown, *Any, Invalid:
+		return true
+	case *Union:
+		return d.Null > 0 || slices.ContainsFunc(d.Variants, acceptsNull)
+	case *Grouped:
+		return true
+	}
+	return d.Nulls() > 0
+}
+
+func (vl *validation) checkString(s *String, path string, v any, jt JsumType) {
+	if jt.t != JsonString {
+		vl.mismatch(path, s, jt)
+		return
+	}
+	var str string
+	switch v := v.(type) {
+	case time.Time:
+		str = v.Format(time.RFC3339)
+	default:
+		str = reflect.ValueOf(v).String()
+	}
+	if s.Format == DateTimeFormat && stringFormat(str) != DateTimeFormat {
+		vl.add(path, TypeMismatch, "%q is no date-time", str)
+	}
+	if s.isEnum() && s.Stats[str] == 0 {
+		vl.add(path, UnseenValue, "%q was not seen", str)
+	}
+}
+
+func (vl *validation) checkNumber(nr *Number, path string, v any, jt JsumType) {
+	if jt.t != JsonNumber {
+		vl.mismatch(path, nr, jt)
+		return
+	}
+	x, _ := asNumber(v, jt.v)
+	if _, frac := math.Modf(x); frac != 0 && !nr.HasFrac {
+		vl.add(path, TypeMismatch, "%v is no integer", x)
+	}
+	if nr.Count > nr.Null && (x < nr.Min || x > nr.Max) {
+		vl.add(path, OutOfRange, "%v not in [%v, %v]", x, nr.Min, nr.Max)
+	}
+}
+
+func (vl *validation) checkArray(a *Array, path string, v any, jt JsumType) {
+	if jt.t != JsonArray {
+		vl.mismatch(path, a, jt)
+		return
+	}
+	rv := reflect.ValueOf(v)
+	l := rv.Len()
+	if a.MinLen >= 0 && (l < a.MinLen || l > a.MaxLen) {
+		vl.add(path, OutOfRange, "length %d not in [%d, %d]", l, a.MinLen, a.MaxLen)
+	}
+	for i := range l {
+		ed := a.Elem
+		if a.IsTuple() && i < len(a.Tuple) {
+			ed = a.Tuple[i]
+		}
+		vl.check(ed, fmt.Sprintf("%s[%d]", path, i), rv.Index(i).Interface())
+	}
+}
+
+func (vl *validation) checkObject(o *Object, path string, v any, jt JsumType) {
+	m := objSeq(v, jt)
+	if m == nil {
+		vl.mismatch(path, o, jt)
+		return
+	}
+	seen := make(map[string]bool)
+	for n, mv := range sortedMembers(m) {
+		seen[n] = true
+		if om, ok := o.Members[n]; ok {
+			vl.check(om.Ded, path+"."+n, mv)
+		} else {
+			vl.add(path+"."+n, UnknownMember, "member was not seen")
+		}
+	}
+	for _, n := range slices.Sorted(maps.Keys(o.Members)) {
+		om := o.Members[n]
+		if !seen[n] && o.Objects() > 0 && o.Absent(&om) == 0 {
+			vl.add(path+"."+n, MissingMember, "member is mandatory")
+		}
+	}
+}
@@ -0,0 +1,159 @@
+/*
+A tool to analyse the structure of JSON from a set of example JSON values.
+Copyright (C) 2025  Marcus Perlick
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package jsum
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := Config{Union: UnionConfig{Tags: []string{"kind"}}}
+	type obj = map[string]any
+	d := deduceAll(&testCfg,
+		obj{"n": 1.0, "ok": true, "color": "red", "tags": []any{"a"}, "opt": nil},
+		obj{"n": 5.0, "ok": true, "color": "red", "tags": []any{"a", "b"}},
+		obj{"n": 3.0, "ok": true, "color": "blue", "tags": []any{"b"}},
+		obj{"n": 2.0, "ok": true, "color": "blue", "tags": []any{"a"}},
+	)
+	tagged := deduceAll(&cfg,
+		obj{"kind": "a", "x": 1.0},
+		obj{"kind": "b", "s": "s"},
+	)
+	tests := []struct {
+		name string
+		d    Deducer
+		v    any
+		want []string
+	}{
+		{"valid", d,
+			obj{"n": 4.0, "ok": true, "color": "red", "tags": []any{"b"}, "opt": nil},
+			nil},
+		{"unknown member", d,
+			obj{"n": 4.0, "ok": true, "color": "red", "tags": []any{"a"}, "x": 1.0},
+			[]string{"$.x: unknown member"}},
+		{"missing member", d,
+			obj{"ok": true, "color": "red", "tags": []any{"a"}},
+			[]string{"$.n: missing member"}},
+		{"type mismatch", d,
+			obj{"n": "4", "ok": true, "color": "red", "tags": []any{1.0}},
+			[]string{"$.n: type mismatch", "$.tags[0]: type mismatch"}},
+		{"null", d,
+			obj{"n": nil, "ok": true, "color": "red", "tags": []any{"a"}},
+			[]string{"$.n: type mismatch"}},
+		{"out of range", d,
+			obj{"n": 6.0, "ok": true, "color": "red", "tags": []any{"a", "b", "a"}},
+			[]string{"$.n: out of range", "$.tags: out of range"}},
+		{"integer", d,
+			obj{"n": 1.5, "ok": true, "color": "red", "tags": []any{"a"}},
+			[]string{"$.n: type mismatch"}},
+		{"unseen values", d,
+			obj{"n": 2.0, "ok": false, "color": "green", "tags": []any{"a"}},
+			[]string{"$.color: unseen value", "$.ok: unseen value"}},
+		{"tagged", tagged, obj{"kind": "b", "s": "t"}, nil},
+		{"unseen tag", tagged, obj{"kind": "c"}, []string{"$.kind: unseen value"}},
+		{"tagged member", tagged,
+			obj{"kind": "a", "x": "1"},
+			[]string{"$.x: type mismatch"}},
+		{"no object", tagged, "a", []string{"$: type mismatch"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, v := range Validate(test.d, 7, test.v) {
+				if v.Record != 7 {
+					t.Errorf("wrong record number %d", v.Record)
+				}
+				got = append(got, v.Path+": "+v.Kind.String())
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("violations %s", strings.Join(got, "; "))
+			}
+		})
+	}
+}
+
+func TestValidate_union(t *testing.T) {
+	cfg := Config{Union: UnionConfig{
+		MergeRejectMax: 0.5,
+		Combine:        []TypeSet{AllTypes},
+	}}
+	type obj = map[string]any
+	d := deduceAll(&cfg,
+		obj{"a": 1.0, "b": 2.0, "c": 3.0},
+		obj{"x": "s", "y": "t", "z": "u"},
+	)
+	if u, ok := d.(*Union); !ok || len(u.Variants) != 2 {
+		t.Fatalf("deduced no union of two objects: %T", d)
+	}
+	for _, v := range []any{
+		obj{"a": 1.0, "b": 2.0, "c": 3.0},
+		obj{"x": "s", "y": "t", "z": "u"},
+	} {
+		if vs := Validate(d, 1, v); len(vs) != 0 {
+			t.Errorf("violations for training example %v: %v", v, vs)
+		}
+	}
+	vs := Validate(d, 1, obj{"x": "s", "y": "t", "z": 1.0})
+	if len(vs) != 1 || vs[0].Path != "$.z" || vs[0].Kind != TypeMismatch {
+		t.Errorf("unexpected violations %v", vs)
+	}
+}
+
+func TestValidate_order(t *testing.T) {
+	d := deduceAll(&testCfg, map[string]any{"a": 1.0})
+	v := make(map[string]any)
+	for i := range 20 {
+		v[fmt.Sprintf("m%02d", i)] = true
+	}
+	vs := Validate(d, 1, v)
+	if len(vs) != 21 {
+		t.Fatalf("unexpected violations %v", vs)
+	}
+	if !slices.IsSortedFunc(vs[:20], func(a, b Violation) int {
+		return strings.Compare(a.Path, b.Path)
+	}) {
+		t.Errorf("violations not ordered by member name: %v", vs)
+	}
+}
+
+func TestValidate_trainedRecords(t *testing.T) {
+	cfg := Config{Object: ObjectConfig{MaxShapes: 8}}
+	var vs []any
+	for i := range 10 {
+		v := map[string]any{"id": float64(i), "color": "blue", "b": 1.0}
+		switch {
+		case i < 3:
+			v["color"] = "red"
+		case i >= 7:
+			delete(v, "b")
+			v["a"] = 1.0
+		}
+		vs = append(vs, v)
+	}
+	d := Discriminate(FoldRecursion(Compact(deduceAll(&cfg, vs...))))
+	for i, v := range vs {
+		if vl := Validate(d, i+1, v); len(vl) > 0 {
+			t.Errorf("trained record rejected: %v", vl)
+		}
+	}
+}